@@ -0,0 +1,231 @@
+package appconfig
+
+import (
+	"sort"
+	"sync"
+)
+
+// Dimension defines a SavedFile or Data field that can be aggregated.
+type Dimension int
+
+func (d Dimension) String() string {
+	return DimensionString[d]
+}
+
+// Dimensions Defined:
+const (
+	DimENV Dimension = iota // 0
+	DimASI
+	DimEASI
+	DimEASIN
+	DimNode
+	DimDatacenter
+	DimWorkgroup
+	DimType
+	DimPkg
+	DimSrc
+	DimKey
+	DimValue
+	DimAppDomain
+	DimTpl
+)
+
+// DimensionString enables a way to identify a Dimension with a string.
+var DimensionString = [...]string{
+	DimENV:        "env",
+	DimASI:        "asi",
+	DimEASI:       "easi",
+	DimEASIN:      "easin",
+	DimNode:       "node",
+	DimDatacenter: "datacenter",
+	DimWorkgroup:  "workgroup",
+	DimType:       "type",
+	DimPkg:        "pkg",
+	DimSrc:        "src",
+	DimKey:        "k",
+	DimValue:      "v",
+	DimAppDomain:  "appdomain",
+	DimTpl:        "tpls",
+}
+
+// IsData returns true if the Dimension is a Data field, false if it is a SavedFile field.
+func (d Dimension) IsData() bool {
+	return d >= DimType
+}
+
+// ValueCount is a value found for a Dimension along with the number of times it was found.
+// SavedFile dimensions count SavedFiles, Data dimensions count Data entries.
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Aggregation holds the ValueCounts, sorted by value, found for each aggregated Dimension.
+type Aggregation map[Dimension][]ValueCount
+
+// Values returns the sorted unique values found for the Dimension.
+func (a Aggregation) Values(d Dimension) []string {
+	counts := a[d]
+	if len(counts) < 1 {
+		return nil
+	}
+	vals := make([]string, len(counts))
+	for i, vc := range counts {
+		vals[i] = vc.Value
+	}
+	return vals
+}
+
+// Count returns the number of times the value was found for the Dimension.
+func (a Aggregation) Count(d Dimension, value string) int {
+	counts := a[d]
+	i := sort.Search(len(counts), func(i int) bool { return counts[i].Value >= value })
+	if i < len(counts) && counts[i].Value == value {
+		return counts[i].Count
+	}
+	return 0
+}
+
+// Aggregate computes the given Dimensions over the SavedState in a single pass.
+func (s SavedState) Aggregate(dims ...Dimension) Aggregation {
+	return s.aggregate(dims).sorted()
+}
+
+// AggregateParallel computes the given Dimensions over the SavedState, splitting it into shards processed by at most the given number of workers.
+// The result is the same as Aggregate.
+func (s SavedState) AggregateParallel(workers int, dims ...Dimension) Aggregation {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(s) {
+		workers = len(s)
+	}
+	if workers < 2 {
+		return s.Aggregate(dims...)
+	}
+	size := (len(s) + workers - 1) / workers
+	// rounding the shard size up can leave fewer non-empty shards than workers.
+	workers = (len(s) + size - 1) / size
+	shards := make([]counts, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		start, end := i*size, (i+1)*size
+		if end > len(s) {
+			end = len(s)
+		}
+		wg.Add(1)
+		go func(i int, shard SavedState) {
+			defer wg.Done()
+			shards[i] = shard.aggregate(dims)
+		}(i, s[start:end])
+	}
+	wg.Wait()
+	merged := shards[0]
+	for _, shard := range shards[1:] {
+		merged.merge(shard)
+	}
+	return merged.sorted()
+}
+
+// counts holds the number of times each value was found per Dimension.
+type counts map[Dimension]map[string]int
+
+func (s SavedState) aggregate(dims []Dimension) counts {
+	c := make(counts, len(dims))
+	var fileDims, dataDims []Dimension
+	for _, d := range dims {
+		if _, ok := c[d]; ok {
+			continue
+		}
+		c[d] = make(map[string]int)
+		switch {
+		case d.IsData():
+			dataDims = append(dataDims, d)
+		default:
+			fileDims = append(fileDims, d)
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		sf := &s[i]
+		for _, d := range fileDims {
+			c[d][fileField(sf, d)]++
+		}
+		if len(dataDims) < 1 {
+			continue
+		}
+		data := sf.Collection()
+		for j := 0; j < len(data); j++ {
+			for _, d := range dataDims {
+				switch d {
+				case DimTpl:
+					// each tpl is counted once per Data, as NewIndex and TplInventory do.
+					for _, tpl := range filterUnique(data[j].Tpls) {
+						c[d][tpl]++
+					}
+				default:
+					c[d][dataField(&data[j], d)]++
+				}
+			}
+		}
+	}
+	return c
+}
+
+func (c counts) merge(o counts) {
+	for d, vals := range o {
+		for v, n := range vals {
+			c[d][v] += n
+		}
+	}
+}
+
+func (c counts) sorted() Aggregation {
+	agg := make(Aggregation, len(c))
+	for d, vals := range c {
+		vcs := make([]ValueCount, 0, len(vals))
+		for v, n := range vals {
+			vcs = append(vcs, ValueCount{Value: v, Count: n})
+		}
+		sort.Slice(vcs, func(i, j int) bool { return vcs[i].Value < vcs[j].Value })
+		agg[d] = vcs
+	}
+	return agg
+}
+
+func fileField(s *SavedFile, d Dimension) string {
+	switch d {
+	case DimENV:
+		return s.ENV
+	case DimASI:
+		return s.ASI
+	case DimEASI:
+		return s.EASI
+	case DimEASIN:
+		return s.EASIN
+	case DimNode:
+		return s.Node
+	case DimDatacenter:
+		return s.Datacenter
+	case DimWorkgroup:
+		return s.Workgroup
+	}
+	return ""
+}
+
+func dataField(d *Data, dim Dimension) string {
+	switch dim {
+	case DimType:
+		return d.T
+	case DimPkg:
+		return d.Pkg
+	case DimSrc:
+		return d.Src
+	case DimKey:
+		return d.Key
+	case DimValue:
+		return d.Value
+	case DimAppDomain:
+		return d.AppDomain
+	}
+	return ""
+}
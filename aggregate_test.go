@@ -0,0 +1,52 @@
+package appconfig
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	var SS SavedState
+	for i := 0; i < 10; i++ {
+		env := `srv`
+		if i%3 == 0 {
+			env = `prd`
+		}
+		node := fmt.Sprintf("node%d", i)
+		SS = append(SS, testFile(env, node, 0,
+			Data{T: `parameter`, Key: `ports__ENVOY_HTTP_PORT`, Src: `default`, Value: `8000`, Tpls: []string{`config/envoy.yaml`}},
+			Data{T: `simple`, Key: `node`, Src: `environment`, Value: node},
+		))
+	}
+	dims := []Dimension{DimENV, DimNode, DimType, DimSrc, DimTpl, DimENV}
+	agg := SS.Aggregate(dims...)
+	switch {
+	case !reflect.DeepEqual(agg[DimENV], []ValueCount{{`prd`, 4}, {`srv`, 6}}):
+		t.Fatalf("unexpected env aggregation: %+v", agg[DimENV])
+	case len(agg.Values(DimNode)) != 10 || agg.Values(DimNode)[0] != `node0`:
+		t.Fatalf("unexpected node aggregation: %v", agg.Values(DimNode))
+	case agg.Count(DimType, `parameter`) != 10 || agg.Count(DimTpl, `config/envoy.yaml`) != 10 || agg.Count(DimSrc, `nope`) != 0:
+		t.Fatalf("unexpected data aggregation: %+v", agg)
+	case len(agg) != 5:
+		t.Fatalf("incorrect number of dimensions, expected %v, got %v", 5, len(agg))
+	}
+	for _, workers := range []int{0, 1, 3, 4, 20} {
+		if par := SS.AggregateParallel(workers, dims...); !reflect.DeepEqual(agg, par) {
+			t.Fatalf("parallel aggregation with %v workers differs: %+v", workers, par)
+		}
+	}
+	for _, workers := range []int{4, 5, 6} {
+		if par, seq := SS[:7].AggregateParallel(workers, dims...), SS[:7].Aggregate(dims...); !reflect.DeepEqual(seq, par) {
+			t.Fatalf("parallel aggregation of 7 saved files with %v workers differs: %+v", workers, par)
+		}
+	}
+	dupes := SavedState{testFile(`srv`, `a`, 0, Data{Tpls: []string{`config/envoy.yaml`, `config/envoy.yaml`}})}
+	if n := dupes.Aggregate(DimTpl).Count(DimTpl, `config/envoy.yaml`); n != 1 {
+		t.Fatalf("expected a repeated tpl to be counted once per data, got %v", n)
+	}
+	envs, _, _, easins, nodes := SS.GetAll()
+	if len(envs) != 2 || len(easins) != 10 || len(nodes) != 10 {
+		t.Fatalf("unexpected GetAll results")
+	}
+}
@@ -0,0 +1,118 @@
+package appconfig
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBroker is an in-memory stand-in for a Kafka cluster.
+// Each topic has a single partition and committed offsets are tracked per consumer group.
+type MemoryBroker struct {
+	mu        sync.Mutex
+	topics    map[string][][]byte
+	committed map[string]map[string]int64
+	notify    chan struct{}
+}
+
+// NewMemoryBroker returns a new MemoryBroker.
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		topics:    make(map[string][][]byte),
+		committed: make(map[string]map[string]int64),
+		notify:    make(chan struct{}),
+	}
+}
+
+// Produce appends the values to the topic and returns the offset of the last one.
+func (b *MemoryBroker) Produce(topic string, values ...[]byte) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.topics[topic] = append(b.topics[topic], values...)
+	close(b.notify)
+	b.notify = make(chan struct{})
+	return int64(len(b.topics[topic]) - 1)
+}
+
+// Committed returns the next offset to be consumed for the given topic and group.
+func (b *MemoryBroker) Committed(topic, group string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.committed[group][topic]
+}
+
+// Source returns a Source reading the topic from the group's committed offset.
+func (b *MemoryBroker) Source(topic, group string) Source {
+	return &memorySource{
+		broker: b,
+		topic:  topic,
+		group:  group,
+		offset: b.Committed(topic, group),
+		closed: make(chan struct{}),
+	}
+}
+
+type memorySource struct {
+	broker    *MemoryBroker
+	topic     string
+	group     string
+	offset    int64
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (m *memorySource) Fetch(ctx context.Context) (Record, error) {
+	for {
+		select {
+		case <-m.closed:
+			return Record{}, ErrSourceClosed
+		default:
+		}
+		b := m.broker
+		b.mu.Lock()
+		msgs := b.topics[m.topic]
+		if m.offset < int64(len(msgs)) {
+			rec := Record{
+				Topic:  m.topic,
+				Offset: m.offset,
+				Value:  msgs[m.offset],
+			}
+			m.offset++
+			b.mu.Unlock()
+			return rec, nil
+		}
+		notify := b.notify
+		b.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return Record{}, ctx.Err()
+		case <-m.closed:
+			return Record{}, ErrSourceClosed
+		case <-notify:
+		}
+	}
+}
+
+func (m *memorySource) Commit(rec Record) error {
+	select {
+	case <-m.closed:
+		return ErrSourceClosed
+	default:
+	}
+	b := m.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.committed[m.group] == nil {
+		b.committed[m.group] = make(map[string]int64)
+	}
+	if rec.Offset+1 > b.committed[m.group][m.topic] {
+		b.committed[m.group][m.topic] = rec.Offset + 1
+	}
+	return nil
+}
+
+func (m *memorySource) Close() error {
+	m.closeOnce.Do(func() {
+		close(m.closed)
+	})
+	return nil
+}
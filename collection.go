@@ -92,79 +92,37 @@ func (c Collection) HasType(dt DataType) bool {
 
 // FromType returns a sub Collection containing only the specified DataType.
 func (c Collection) FromType(dt DataType) Collection {
-	var data []Data
-	for _, d := range c {
-		if d.DataType() == dt {
-			data = append(data, d)
-		}
-	}
-	return data
+	return c.Filter(TypeIs(dt))
 }
 
 // FromPkg returns a sub Collection containing only the data that contains a reference to the given pkg.
 func (c Collection) FromPkg(pkg string) Collection {
-	var data []Data
-	for _, d := range c {
-		if d.HasPkg(pkg) {
-			data = append(data, d)
-		}
-	}
-	return data
+	return c.Filter(PkgIs(pkg))
 }
 
 // FromPkgRegexp returns a sub Collection containing only the data whose pkg matches the given regexp.
 func (c Collection) FromPkgRegexp(regex *regexp.Regexp) Collection {
-	var data []Data
-	for _, d := range c {
-		if regex.MatchString(d.Pkg) {
-			data = append(data, d)
-		}
-	}
-	return data
+	return c.Filter(PkgMatches(regex))
 }
 
 // FromKey returns a sub Collection containing only the data that contains a reference to the given key.
 func (c Collection) FromKey(key string) Collection {
-	var data []Data
-	for _, d := range c {
-		if d.HasKey(key) {
-			data = append(data, d)
-		}
-	}
-	return data
+	return c.Filter(KeyIs(key))
 }
 
 // FromKeyRegexp returns a sub Collection containing only the data whose key matches the given regexp.
 func (c Collection) FromKeyRegexp(regex *regexp.Regexp) Collection {
-	var data []Data
-	for _, d := range c {
-		if regex.MatchString(d.Key) {
-			data = append(data, d)
-		}
-	}
-	return data
+	return c.Filter(KeyMatches(regex))
 }
 
 // FromAD returns a sub Collection containing only the data that contains a reference to the given AppDomain.
 func (c Collection) FromAD(ad string) Collection {
-	var data []Data
-	for _, d := range c {
-		if d.HasAD(ad) {
-			data = append(data, d)
-		}
-	}
-	return data
+	return c.Filter(ADIs(ad))
 }
 
 // FromADRegexp returns a sub Collection containing only the data if the AppDomain matches the given regexp.
 func (c Collection) FromADRegexp(regex *regexp.Regexp) Collection {
-	var data []Data
-	for _, d := range c {
-		if regex.MatchString(d.AppDomain) {
-			data = append(data, d)
-		}
-	}
-	return data
+	return c.Filter(ADMatches(regex))
 }
 
 // Get returns all the values found in the Collection if the given key matches.
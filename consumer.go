@@ -0,0 +1,101 @@
+package appconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// DefaultTopic is the Kafka topic state files are published to.
+const DefaultTopic = `srv-appconfig-event-json`
+
+// ErrSourceClosed is returned when fetching from a closed Source.
+var ErrSourceClosed = errors.New("source closed")
+
+// Record is a single message fetched from a Source.
+type Record struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Value     []byte
+}
+
+// Source is a stream of Records, such as a Kafka topic.
+type Source interface {
+	// Fetch blocks until the next Record is available or the context is done.
+	Fetch(ctx context.Context) (Record, error)
+	// Commit marks the Record, and all Records before it, as consumed.
+	Commit(Record) error
+	Close() error
+}
+
+// RecordError is reported when a Record could not be decoded.
+type RecordError struct {
+	Record Record
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("%s/%d@%d: %v", e.Record.Topic, e.Record.Partition, e.Record.Offset, e.Err)
+}
+
+// Consumer reads Records from a Source, decodes them into SavedFiles and feeds them into a Sink.
+type Consumer struct {
+	Source Source
+	Sink   Sink
+	// OnError, if set, is called for each Record that could not be decoded.
+	// Such Records are committed and skipped.
+	OnError func(*RecordError)
+}
+
+// NewConsumer returns a new Consumer.
+func NewConsumer(source Source, sink Sink) *Consumer {
+	return &Consumer{
+		Source: source,
+		Sink:   sink,
+	}
+}
+
+// Run consumes Records until the context is done or the Source is closed.
+// A Record is only committed once its SavedFile has been accepted by the Sink.
+// The Source being closed stops Run without error, whether seen by Fetch or Commit.
+// A Record whose Commit failed this way is fetched again by the next Consumer of the group.
+func (c *Consumer) Run(ctx context.Context) error {
+	for {
+		rec, err := c.Source.Fetch(ctx)
+		switch {
+		case err == ErrSourceClosed:
+			return nil
+		case err != nil:
+			return err
+		}
+		savedFile, err := DecodeRecord(rec)
+		switch {
+		case err != nil:
+			if c.OnError != nil {
+				c.OnError(&RecordError{Record: rec, Err: err})
+			}
+		default:
+			if err := c.Sink.Put(savedFile); err != nil {
+				return err
+			}
+		}
+		switch err := c.Source.Commit(rec); {
+		case err == ErrSourceClosed:
+			return nil
+		case err != nil:
+			return err
+		}
+	}
+}
+
+// DecodeRecord returns the SavedFile contained in a Record.
+func DecodeRecord(rec Record) (savedFile SavedFile, err error) {
+	var kMsg KafkaMSG
+	err = json.Unmarshal(rec.Value, &kMsg)
+	if err != nil {
+		return
+	}
+	return kMsg.SavedFile()
+}
@@ -0,0 +1,73 @@
+package appconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConsumer(t *testing.T) {
+	broker := NewMemoryBroker()
+	broker.Produce(DefaultTopic, []byte(rawKafkaMsg), []byte(`{bad json`), []byte(rawKafkaMsg))
+	store := NewStateStore()
+	source := broker.Source(DefaultTopic, `test`)
+	consumer := NewConsumer(source, store)
+	var errs []*RecordError
+	consumer.OnError = func(err *RecordError) {
+		errs = append(errs, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- consumer.Run(ctx)
+	}()
+	for broker.Committed(DefaultTopic, `test`) < 3 {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for commits")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	source.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("consumer returned error: %v", err)
+	}
+	switch {
+	case len(errs) != 1 || errs[0].Record.Offset != 1:
+		t.Fatalf("expected one decode error at offset 1, got %v", errs)
+	case store.Len() != 1:
+		t.Fatalf("incorrect number of saved files, expected %v, got %v", 1, store.Len())
+	case !store.SavedState()[0].HasNode(`srv24w0m15`):
+		t.Fatalf("saved file did not have expected node value")
+	}
+}
+
+// closingSink closes the Source on each Put, before the Consumer can commit.
+type closingSink struct {
+	source Source
+	n      int
+}
+
+func (s *closingSink) Put(SavedFile) error {
+	s.n++
+	return s.source.Close()
+}
+
+func TestConsumerClosedOnCommit(t *testing.T) {
+	broker := NewMemoryBroker()
+	broker.Produce(DefaultTopic, []byte(rawKafkaMsg))
+	source := broker.Source(DefaultTopic, `test`)
+	sink := &closingSink{source: source}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := NewConsumer(source, sink).Run(ctx); err != nil {
+		t.Fatalf("consumer returned error: %v", err)
+	}
+	switch {
+	case sink.n != 1:
+		t.Fatalf("incorrect number of puts, expected %v, got %v", 1, sink.n)
+	case broker.Committed(DefaultTopic, `test`) != 0:
+		t.Fatalf("expected no commits after the source was closed")
+	}
+}
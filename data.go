@@ -71,10 +71,10 @@ func (d *Data) AssignAD(defaultValue ...string) {
 	}
 	switch d.DataType() {
 	case TypeEndpoint:
-		ad := epAD(d.Value)
+		eps, err := ParseEndpoints(d.Value)
 		switch {
-		case ad != "":
-			d.AppDomain = ad
+		case err == nil:
+			d.AppDomain = eps[len(eps)-1].AppDomain
 		default:
 			if d.AppDomain == "" {
 				d.AppDomain = dv
@@ -0,0 +1,205 @@
+package appconfig
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// MaxLineSize is the largest line, in bytes, a Decoder will accept.
+var MaxLineSize = 16 * 1024 * 1024
+
+// ErrLineTooLong is returned, wrapped in a *LineError, for a line larger than MaxLineSize.
+var ErrLineTooLong = errors.New("line too long")
+
+// LineError is returned by a Decoder when a line could not be decoded.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// DecodeResult is a single decoded line produced by Decoder.Stream.
+type DecodeResult struct {
+	Line      int
+	SavedFile SavedFile
+	Err       error
+}
+
+// Decoder reads KafkaMSGs, one JSON object per line, and decodes them into SavedFiles.
+type Decoder struct {
+	reader  *bufio.Reader
+	maxLine int
+	line    int
+	done    bool
+}
+
+// NewDecoder returns a new Decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		reader:  bufio.NewReaderSize(r, 64*1024),
+		maxLine: MaxLineSize,
+	}
+}
+
+// Next returns the next SavedFile from the stream.
+// A line that fails to decode returns a *LineError and the stream can continue to be read.
+// Blank lines are skipped and io.EOF is returned once the stream is exhausted.
+func (d *Decoder) Next() (savedFile SavedFile, err error) {
+	line, raw, err := d.nextLine()
+	if err != nil {
+		return
+	}
+	return decodeLine(line, raw)
+}
+
+// Stream decodes the remaining lines using the given number of workers and sends the results in the order they were read.
+// The returned channel is closed once the stream is exhausted or ctx is done, cancel ctx to stop reading early.
+func (d *Decoder) Stream(ctx context.Context, workers int) <-chan DecodeResult {
+	if workers < 1 {
+		workers = 1
+	}
+	type job struct {
+		line   int
+		raw    []byte
+		result chan DecodeResult
+	}
+	jobs := make(chan job, workers)
+	queue := make(chan chan DecodeResult, workers)
+	results := make(chan DecodeResult, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func(wg *sync.WaitGroup) {
+			defer wg.Done()
+			for j := range jobs {
+				sf, err := decodeLine(j.line, j.raw)
+				// result is buffered, so this never blocks.
+				j.result <- DecodeResult{Line: j.line, SavedFile: sf, Err: err}
+			}
+		}(&wg)
+	}
+	go func() {
+		defer close(queue)
+		defer close(jobs)
+		for {
+			line, raw, err := d.nextLine()
+			if err == io.EOF {
+				return
+			}
+			result := make(chan DecodeResult, 1)
+			select {
+			case queue <- result:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				result <- DecodeResult{Line: line, Err: err}
+				continue
+			}
+			select {
+			case jobs <- job{line: line, raw: raw, result: result}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		defer close(results)
+		defer wg.Wait()
+		for result := range queue {
+			var res DecodeResult
+			select {
+			case res = <-result:
+			case <-ctx.Done():
+				drainResults(queue)
+				return
+			}
+			select {
+			case results <- res:
+			case <-ctx.Done():
+				drainResults(queue)
+				return
+			}
+		}
+	}()
+	return results
+}
+
+func (d *Decoder) nextLine() (int, []byte, error) {
+	for !d.done {
+		raw, err := d.readLine()
+		switch {
+		case err == io.EOF:
+			d.done = true
+			if len(raw) == 0 {
+				return d.line, nil, io.EOF
+			}
+		case err != nil && err != ErrLineTooLong:
+			d.done = true
+			return d.line + 1, nil, &LineError{Line: d.line + 1, Err: err}
+		}
+		d.line++
+		if err == ErrLineTooLong {
+			return d.line, nil, &LineError{Line: d.line, Err: err}
+		}
+		raw = bytes.TrimSpace(raw)
+		if len(raw) == 0 {
+			continue
+		}
+		return d.line, raw, nil
+	}
+	return d.line, nil, io.EOF
+}
+
+// readLine returns the next line without its newline.
+// A line larger than the Decoder's maximum is skipped and ErrLineTooLong returned.
+func (d *Decoder) readLine() ([]byte, error) {
+	var line []byte
+	tooLong := false
+	for {
+		chunk, err := d.reader.ReadSlice('\n')
+		if !tooLong {
+			line = append(line, chunk...)
+			// allow for the newline.
+			if len(line) > d.maxLine+1 {
+				tooLong, line = true, nil
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		line = bytes.TrimSuffix(line, []byte{'\n'})
+		if tooLong || len(line) > d.maxLine {
+			return nil, ErrLineTooLong
+		}
+		return line, err
+	}
+}
+
+// drainResults discards the remaining queued results so the reader is never blocked.
+func drainResults(queue chan chan DecodeResult) {
+	for range queue {
+	}
+}
+
+func decodeLine(line int, raw []byte) (savedFile SavedFile, err error) {
+	var kMsg KafkaMSG
+	err = json.Unmarshal(raw, &kMsg)
+	if err != nil {
+		return savedFile, &LineError{Line: line, Err: err}
+	}
+	savedFile, err = kMsg.SavedFile()
+	if err != nil {
+		return savedFile, &LineError{Line: line, Err: err}
+	}
+	return
+}
@@ -0,0 +1,89 @@
+package appconfig
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	lines := []string{rawKafkaMsg, `{bad json`, ``, rawKafkaMsg, `{"message":"not a statefile"}`, rawKafkaMsg}
+	input := strings.Join(lines, "\n")
+	var good, bad []int
+	dec := NewDecoder(strings.NewReader(input))
+	for {
+		_, err := dec.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			lErr, ok := err.(*LineError)
+			if !ok {
+				t.Fatalf("expected *LineError, got %T", err)
+			}
+			bad = append(bad, lErr.Line)
+			continue
+		}
+		good = append(good, dec.line)
+	}
+	if len(good) != 3 || len(bad) != 2 || bad[0] != 2 || bad[1] != 5 {
+		t.Fatalf("unexpected decode results, good %v, bad %v", good, bad)
+	}
+	var order []int
+	for res := range NewDecoder(strings.NewReader(input)).Stream(context.Background(), 4) {
+		order = append(order, res.Line)
+		if res.Err == nil && res.SavedFile.Node != `srv24w0m15` {
+			t.Fatalf("incorrect node on line %v, got %q", res.Line, res.SavedFile.Node)
+		}
+	}
+	expected := []int{1, 2, 4, 5, 6}
+	if len(order) != len(expected) {
+		t.Fatalf("incorrect number of results, expected %v, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("results out of order, expected %v, got %v", expected, order)
+		}
+	}
+}
+
+func TestDecoderLineTooLong(t *testing.T) {
+	long := `{"message":"` + strings.Repeat("x", len(rawKafkaMsg)) + `"}`
+	input := strings.Join([]string{rawKafkaMsg, long, rawKafkaMsg}, "\n")
+	dec := NewDecoder(strings.NewReader(input))
+	dec.maxLine = len(rawKafkaMsg)
+	var lines []int
+	for {
+		_, err := dec.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			lErr, ok := err.(*LineError)
+			if !ok || lErr.Err != ErrLineTooLong || lErr.Line != 2 {
+				t.Fatalf("expected line 2 to be too long, got %v", err)
+			}
+			continue
+		}
+		lines = append(lines, dec.line)
+	}
+	if len(lines) != 2 || lines[1] != 3 {
+		t.Fatalf("expected lines 1 and 3 to decode, got %v", lines)
+	}
+}
+
+func TestDecoderStreamCancel(t *testing.T) {
+	input := strings.Repeat(rawKafkaMsg+"\n", 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	results := NewDecoder(strings.NewReader(input)).Stream(ctx, 4)
+	<-results
+	cancel()
+	n := 0
+	for range results {
+		n++
+	}
+	if n >= 99 {
+		t.Fatalf("expected the stream to stop early, got %v more results", n)
+	}
+}
@@ -0,0 +1,156 @@
+package appconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Change is a single difference between two Collections for a pkg and key.
+// Old is nil for added entries and New is nil for removed entries.
+type Change struct {
+	Pkg    string   `json:"pkg"`
+	Key    string   `json:"k"`
+	Old    *Data    `json:"old,omitempty"`
+	New    *Data    `json:"new,omitempty"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Diff is the set of differences between two Collections.
+type Diff struct {
+	Added   []Change `json:"added,omitempty"`
+	Removed []Change `json:"removed,omitempty"`
+	Changed []Change `json:"changed,omitempty"`
+}
+
+// DiffCollections compares two Collections, keyed by pkg and key, and returns the differences.
+// When a pkg and key is found more than once, the entry winning under DefaultPrecedence is compared.
+func DiffCollections(older, newer Collection) Diff {
+	oldMap, newMap := diffMap(older), diffMap(newer)
+	var diff Diff
+	for k, o := range oldMap {
+		n, ok := newMap[k]
+		if !ok {
+			o := o
+			diff.Removed = append(diff.Removed, Change{Pkg: k.pkg, Key: k.key, Old: &o})
+			continue
+		}
+		if fields := changedFields(o, n); len(fields) > 0 {
+			o, n := o, n
+			diff.Changed = append(diff.Changed, Change{Pkg: k.pkg, Key: k.key, Old: &o, New: &n, Fields: fields})
+		}
+	}
+	for k, n := range newMap {
+		if _, ok := oldMap[k]; !ok {
+			n := n
+			diff.Added = append(diff.Added, Change{Pkg: k.pkg, Key: k.key, New: &n})
+		}
+	}
+	sortChanges(diff.Added)
+	sortChanges(diff.Removed)
+	sortChanges(diff.Changed)
+	return diff
+}
+
+// Diff compares the StateFile with a newer StateFile and returns the differences.
+func (s *StateFile) Diff(newer StateFile) Diff {
+	return DiffCollections(s.Collection, newer.Collection)
+}
+
+// IsEmpty returns true if there are no differences.
+func (d Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Len returns the total number of differences.
+func (d Diff) Len() int {
+	return len(d.Added) + len(d.Removed) + len(d.Changed)
+}
+
+// JSON returns the Diff rendered as JSON.
+func (d Diff) JSON() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+// Text returns the Diff rendered as text.
+func (d Diff) Text() string {
+	var buf bytes.Buffer
+	d.WriteText(&buf)
+	return buf.String()
+}
+
+// WriteText writes the Diff rendered as text to w.
+// Added entries are prefixed with +, removed entries with - and changed entries with ~ followed by each changed field.
+func (d Diff) WriteText(w io.Writer) error {
+	for _, c := range d.Added {
+		if _, err := fmt.Fprintf(w, "+ %s/%s: %s\n", c.Pkg, c.Key, dataText(c.New)); err != nil {
+			return err
+		}
+	}
+	for _, c := range d.Removed {
+		if _, err := fmt.Fprintf(w, "- %s/%s: %s\n", c.Pkg, c.Key, dataText(c.Old)); err != nil {
+			return err
+		}
+	}
+	for _, c := range d.Changed {
+		if _, err := fmt.Fprintf(w, "~ %s/%s:\n", c.Pkg, c.Key); err != nil {
+			return err
+		}
+		for _, f := range c.Fields {
+			if _, err := fmt.Fprintf(w, "    %s: %s -> %s\n", f, fieldText(c.Old, f), fieldText(c.New, f)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func diffMap(c Collection) map[pkgKey]Data {
+	resolutions := c.Resolve(DefaultPrecedence)
+	m := make(map[pkgKey]Data, len(resolutions))
+	for _, res := range resolutions {
+		m[pkgKey{pkg: res.Pkg, key: res.Key}] = res.Winner
+	}
+	return m
+}
+
+func changedFields(o, n Data) (fields []string) {
+	for _, f := range []string{`type`, `v`, `src`, `tpls`, `appdomain`} {
+		if fieldText(&o, f) != fieldText(&n, f) {
+			fields = append(fields, f)
+		}
+	}
+	return
+}
+
+func fieldText(d *Data, field string) string {
+	switch field {
+	case `type`:
+		return d.T
+	case `v`:
+		return fmt.Sprintf("%q", d.Value)
+	case `src`:
+		return d.Src
+	case `tpls`:
+		return `[` + strings.Join(d.Tpls, ` `) + `]`
+	case `appdomain`:
+		return d.AppDomain
+	}
+	return ""
+}
+
+func dataText(d *Data) string {
+	return fmt.Sprintf("v=%s src=%s tpls=%s appdomain=%s", fieldText(d, `v`), d.Src, fieldText(d, `tpls`), d.AppDomain)
+}
+
+func sortChanges(changes []Change) {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Pkg != changes[j].Pkg {
+			return changes[i].Pkg < changes[j].Pkg
+		}
+		return changes[i].Key < changes[j].Key
+	})
+}
@@ -0,0 +1,49 @@
+package appconfig
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	pkg := `packapi-sit20191024.103-0`
+	old := StateFile{Collection: Collection{
+		{T: `parameter`, Pkg: pkg, Key: `ports__ENVOY_HTTP_PORT`, Src: `default`, Value: `8080`, Tpls: []string{`config/envoy.yaml`}},
+		{T: `simple`, Pkg: pkg, Key: `uptime_days`, Src: `facter`, Value: `17`},
+		{T: `simple`, Pkg: pkg, Key: `timezone`, Src: `facter`, Value: `EDT`},
+	}}
+	newer := StateFile{Collection: Collection{
+		{T: `parameter`, Pkg: pkg, Key: `ports__ENVOY_HTTP_PORT`, Src: `appconfig`, Value: `8000`, Tpls: []string{`config/envoy.yaml`}},
+		{T: `simple`, Pkg: pkg, Key: `timezone`, Src: `facter`, Value: `EDT`},
+		{T: `simple`, Pkg: pkg, Key: `node`, Src: `environment`, Value: `srv24w0m15`},
+	}}
+	diff := old.Diff(newer)
+	switch {
+	case len(diff.Added) != 1 || diff.Added[0].Key != `node` || diff.Added[0].Old != nil:
+		t.Fatalf("unexpected added: %+v", diff.Added)
+	case len(diff.Removed) != 1 || diff.Removed[0].Key != `uptime_days` || diff.Removed[0].New != nil:
+		t.Fatalf("unexpected removed: %+v", diff.Removed)
+	case len(diff.Changed) != 1 || len(diff.Changed[0].Fields) != 2:
+		t.Fatalf("unexpected changed: %+v", diff.Changed)
+	}
+	expected := `+ packapi-sit20191024.103-0/node: v="srv24w0m15" src=environment tpls=[] appdomain=
+- packapi-sit20191024.103-0/uptime_days: v="17" src=facter tpls=[] appdomain=
+~ packapi-sit20191024.103-0/ports__ENVOY_HTTP_PORT:
+    v: "8080" -> "8000"
+    src: default -> appconfig
+`
+	if diff.Text() != expected {
+		t.Fatalf("unexpected text diff:\n%s", diff.Text())
+	}
+	b, err := diff.JSON()
+	if err != nil {
+		t.Fatalf("error rendering json: %v", err)
+	}
+	var decoded Diff
+	if err := json.Unmarshal(b, &decoded); err != nil || decoded.Len() != 3 {
+		t.Fatalf("unexpected json diff: %s", b)
+	}
+	if !old.Diff(old).IsEmpty() {
+		t.Fatalf("expected no differences comparing a statefile with itself")
+	}
+}
@@ -0,0 +1,182 @@
+package appconfig
+
+import "sort"
+
+// DefaultNodeKeys are the keys expected to differ between the nodes of an EASI.
+var DefaultNodeKeys = []string{
+	"node",
+	"environment__e_node",
+	"uptime_days",
+}
+
+// DriftAnalyzer reports keys whose values differ between the nodes of an EASI.
+type DriftAnalyzer struct {
+	// NodeKeys are the keys expected to differ per node, they are never reported.
+	NodeKeys []string
+	// Precedence is used to pick the effective value of a key on each node.
+	Precedence Precedence
+}
+
+// NewDriftAnalyzer returns a new DriftAnalyzer using DefaultNodeKeys and DefaultPrecedence.
+func NewDriftAnalyzer() *DriftAnalyzer {
+	return &DriftAnalyzer{
+		NodeKeys:   append([]string(nil), DefaultNodeKeys...),
+		Precedence: DefaultPrecedence,
+	}
+}
+
+// AppKey identifies a key within an app, the pkg without its version, so a key can be compared across pkg versions.
+type AppKey struct {
+	App string `json:"app"`
+	Key string `json:"k"`
+}
+
+// String returns the AppKey in the form app/key.
+func (a AppKey) String() string {
+	return a.App + `/` + a.Key
+}
+
+func (a AppKey) less(b AppKey) bool {
+	return a.App < b.App || (a.App == b.App && a.Key < b.Key)
+}
+
+func sortAppKeys(keys []AppKey) {
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+}
+
+// AppKey returns the AppKey of the Resolution.
+func (r Resolution) AppKey() AppKey {
+	return AppKey{App: pkgApp(r.Pkg), Key: r.Key}
+}
+
+// Drift is a single key of an app whose value differs between the nodes of an EASI.
+// Outliers maps each value other than the majority value to the nodes holding it, nodes missing the key are listed under Missing.
+type Drift struct {
+	EASI          string              `json:"easi"`
+	App           string              `json:"app"`
+	Key           string              `json:"k"`
+	MajorityValue string              `json:"majority"`
+	MajorityNodes []string            `json:"majorityNodes"`
+	Outliers      map[string][]string `json:"outliers,omitempty"`
+	Missing       []string            `json:"missing,omitempty"`
+}
+
+// DriftReport is the result of analyzing a SavedState for drift, sorted by EASI, app and key.
+type DriftReport []Drift
+
+// FromEASI returns the drift found for the given EASI.
+func (r DriftReport) FromEASI(easi string) DriftReport {
+	var report DriftReport
+	for _, d := range r {
+		if d.EASI == easi {
+			report = append(report, d)
+		}
+	}
+	return report
+}
+
+// GroupByEASI returns the SavedFiles grouped by EASI.
+func (s SavedState) GroupByEASI() map[string]SavedState {
+	groups := make(map[string]SavedState)
+	for _, sf := range s {
+		groups[sf.EASI] = append(groups[sf.EASI], sf)
+	}
+	return groups
+}
+
+// Analyze groups the SavedState by EASI and reports each key whose effective value differs between nodes.
+// Keys are compared per app, so nodes running different versions of a pkg are still compared.
+// EASIs with a single node are skipped.
+func (a *DriftAnalyzer) Analyze(s SavedState) DriftReport {
+	skip := make(map[string]bool, len(a.NodeKeys))
+	for _, k := range a.NodeKeys {
+		skip[k] = true
+	}
+	var report DriftReport
+	groups := s.GroupByEASI()
+	easis := make([]string, 0, len(groups))
+	for easi := range groups {
+		easis = append(easis, easi)
+	}
+	sort.Strings(easis)
+	for _, easi := range easis {
+		group := groups[easi]
+		if len(group) < 2 {
+			continue
+		}
+		// values maps each app key to each node's effective value.
+		values := make(map[AppKey]map[string]string)
+		var nodes []string
+		for _, sf := range group {
+			nodes = append(nodes, sf.Node)
+			for _, res := range sf.Collection().Resolve(a.Precedence) {
+				if skip[res.Key] {
+					continue
+				}
+				k := res.AppKey()
+				if values[k] == nil {
+					values[k] = make(map[string]string)
+				}
+				values[k][sf.Node] = res.Winner.Value
+			}
+		}
+		sort.Strings(nodes)
+		keys := make([]AppKey, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sortAppKeys(keys)
+		for _, key := range keys {
+			if d, ok := drift(easi, key, nodes, values[key]); ok {
+				report = append(report, d)
+			}
+		}
+	}
+	return report
+}
+
+// Drift analyzes the SavedState using a DriftAnalyzer with DefaultNodeKeys and DefaultPrecedence.
+func (s SavedState) Drift() DriftReport {
+	return NewDriftAnalyzer().Analyze(s)
+}
+
+func drift(easi string, key AppKey, nodes []string, byNode map[string]string) (Drift, bool) {
+	byValue := make(map[string][]string)
+	var missing []string
+	for _, node := range nodes {
+		v, ok := byNode[node]
+		if !ok {
+			missing = append(missing, node)
+			continue
+		}
+		byValue[v] = append(byValue[v], node)
+	}
+	if len(byValue) < 2 && len(missing) == 0 {
+		return Drift{}, false
+	}
+	var majority string
+	for v, n := range byValue {
+		m := byValue[majority]
+		if len(n) > len(m) || (len(n) == len(m) && v < majority) || m == nil {
+			majority = v
+		}
+	}
+	d := Drift{
+		EASI:          easi,
+		App:           key.App,
+		Key:           key.Key,
+		MajorityValue: majority,
+		MajorityNodes: byValue[majority],
+		Missing:       missing,
+	}
+	for v, n := range byValue {
+		if v == majority {
+			continue
+		}
+		if d.Outliers == nil {
+			d.Outliers = make(map[string][]string)
+		}
+		d.Outliers[v] = n
+	}
+	return d, true
+}
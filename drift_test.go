@@ -0,0 +1,42 @@
+package appconfig
+
+import "testing"
+
+func TestDrift(t *testing.T) {
+	pkg := `packapi-sit20191024.103-0`
+	node := func(name, pkg, port, timeout string) SavedFile {
+		c := Collection{
+			{Pkg: pkg, Key: `environment__e_node`, Src: `environment`, Value: name},
+			{Pkg: pkg, Key: `ports__ENVOY_HTTP_PORT`, Src: `default`, Value: port},
+		}
+		if timeout != "" {
+			c = append(c, Data{Pkg: pkg, Key: `properties__deq-ack-timeout`, Src: `default`, Value: timeout})
+		}
+		return testFile(`srv`, name, 0, c...)
+	}
+	SS := SavedState{
+		node(`a`, pkg, `8000`, `30`),
+		node(`b`, pkg, `8000`, `30`),
+		// c runs a newer pkg version, its keys are still compared with the other nodes.
+		node(`c`, `packapi-sit20191025.104-0`, `8001`, ``),
+		{EASI: `srv:wm:app:other`, Node: `a`},
+	}
+	report := SS.Drift()
+	if len(report) != 2 {
+		t.Fatalf("incorrect number of drifts, expected %v, got %v: %+v", 2, len(report), report)
+	}
+	port, timeout := report[0], report[1]
+	switch {
+	case port.App != `packapi` || port.Key != `ports__ENVOY_HTTP_PORT` || port.MajorityValue != `8000` || len(port.MajorityNodes) != 2:
+		t.Fatalf("unexpected port drift: %+v", port)
+	case len(port.Outliers[`8001`]) != 1 || port.Outliers[`8001`][0] != `c`:
+		t.Fatalf("unexpected port outliers: %+v", port.Outliers)
+	case timeout.Key != `properties__deq-ack-timeout` || len(timeout.Missing) != 1 || timeout.Missing[0] != `c`:
+		t.Fatalf("unexpected timeout drift: %+v", timeout)
+	}
+	analyzer := NewDriftAnalyzer()
+	analyzer.NodeKeys = append(analyzer.NodeKeys, `ports__ENVOY_HTTP_PORT`)
+	if n := len(analyzer.Analyze(SS).FromEASI(`srv:wm:app:packapi`)); n != 1 {
+		t.Fatalf("incorrect number of drifts, expected %v, got %v", 1, n)
+	}
+}
@@ -0,0 +1,139 @@
+package appconfig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Endpoint is a parsed endpoint value in the form host:port:protocol:appdomain.
+type Endpoint struct {
+	Key       string `json:"k"`
+	Pkg       string `json:"pkg"`
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	Protocol  string `json:"protocol"`
+	AppDomain string `json:"appdomain"`
+}
+
+// String returns the Endpoint in its original host:port:protocol:appdomain form.
+func (e Endpoint) String() string {
+	return e.Host + `:` + strconv.Itoa(e.Port) + `:` + e.Protocol + `:` + e.AppDomain
+}
+
+// Address returns the host:port of the Endpoint.
+func (e Endpoint) Address() string {
+	return e.Host + `:` + strconv.Itoa(e.Port)
+}
+
+// EndpointError is returned when an endpoint value could not be parsed.
+type EndpointError struct {
+	Key   string
+	Value string
+	Msg   string
+}
+
+func (e *EndpointError) Error() string {
+	if e.Key != "" {
+		return fmt.Sprintf("endpoint %q: invalid value %q: %s", e.Key, e.Value, e.Msg)
+	}
+	return fmt.Sprintf("invalid endpoint value %q: %s", e.Value, e.Msg)
+}
+
+// EndpointErrors is the list of all endpoint values that could not be parsed.
+type EndpointErrors []*EndpointError
+
+func (e EndpointErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, `; `)
+}
+
+// ParseEndpoints parses an endpoint value, which may be a single endpoint or a quoted list of endpoints.
+func ParseEndpoints(value string) (endpoints []Endpoint, err error) {
+	var vals []string
+	switch {
+	case strings.Contains(value, `'`):
+		tmp := strings.Split(value, `'`)
+		if len(tmp)%2 == 0 {
+			return nil, &EndpointError{Value: value, Msg: "unbalanced quotes"}
+		}
+		for i := 1; i < len(tmp); i += 2 {
+			vals = append(vals, tmp[i])
+		}
+	default:
+		vals = []string{strings.TrimSpace(value)}
+	}
+	for _, v := range vals {
+		ep, err := parseEndpoint(v)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, ep)
+	}
+	if len(endpoints) < 1 {
+		return nil, &EndpointError{Value: value, Msg: "no endpoints found"}
+	}
+	return
+}
+
+func parseEndpoint(value string) (ep Endpoint, err error) {
+	x := strings.Split(value, `:`)
+	if len(x) != 4 {
+		return ep, &EndpointError{Value: value, Msg: "expected host:port:protocol:appdomain"}
+	}
+	if x[0] == "" {
+		return ep, &EndpointError{Value: value, Msg: "missing host"}
+	}
+	port, err := strconv.Atoi(x[1])
+	if err != nil || port < 0 || port > 65535 {
+		return ep, &EndpointError{Value: value, Msg: fmt.Sprintf("invalid port %q", x[1])}
+	}
+	ep = Endpoint{
+		Host:      x[0],
+		Port:      port,
+		Protocol:  x[2],
+		AppDomain: x[3],
+	}
+	return ep, nil
+}
+
+// Endpoints returns the Endpoints parsed from the data value.
+// An error is returned if the data is not a TypeEndpoint or its value is malformed.
+func (d *Data) Endpoints() ([]Endpoint, error) {
+	if d.DataType() != TypeEndpoint {
+		return nil, &EndpointError{Key: d.Key, Value: d.Value, Msg: fmt.Sprintf("data type is %v", d.DataType())}
+	}
+	endpoints, err := ParseEndpoints(d.Value)
+	if err != nil {
+		if e, ok := err.(*EndpointError); ok {
+			e.Key = d.Key
+		}
+		return nil, err
+	}
+	for i := 0; i < len(endpoints); i++ {
+		endpoints[i].Key = d.Key
+		endpoints[i].Pkg = d.Pkg
+	}
+	return endpoints, nil
+}
+
+// Endpoints returns the Endpoints parsed from all TypeEndpoint data in the Collection.
+// Malformed values are skipped and returned together as EndpointErrors.
+func (c Collection) Endpoints() (endpoints []Endpoint, err error) {
+	var errs EndpointErrors
+	for _, d := range c.FromType(TypeEndpoint) {
+		eps, err := d.Endpoints()
+		if err != nil {
+			errs = append(errs, err.(*EndpointError))
+			continue
+		}
+		endpoints = append(endpoints, eps...)
+	}
+	if len(errs) > 0 {
+		return endpoints, errs
+	}
+	return endpoints, nil
+}
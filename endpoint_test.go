@@ -0,0 +1,50 @@
+package appconfig
+
+import "testing"
+
+func TestParseEndpoints(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+		fail     bool
+	}{
+		{`wmax.srv.example.com:9030:http:srv1m7`, []string{`wmax.srv.example.com:9030:http:srv1m7`}, false},
+		{`['a.example.com:9030:http:srv1m7', 'b.example.com:9031:https:srv2m7']`, []string{`a.example.com:9030:http:srv1m7`, `b.example.com:9031:https:srv2m7`}, false},
+		{`wmax.srv.example.com:9030:http`, nil, true},
+		{`wmax.srv.example.com:port:http:srv1m7`, nil, true},
+		{`['a.example.com:9030:http:srv1m7`, nil, true},
+	}
+	for _, tt := range tests {
+		eps, err := ParseEndpoints(tt.value)
+		switch {
+		case tt.fail && err == nil:
+			t.Fatalf("expected error parsing %q", tt.value)
+		case !tt.fail && err != nil:
+			t.Fatalf("unexpected error parsing %q: %v", tt.value, err)
+		case len(eps) != len(tt.expected):
+			t.Fatalf("incorrect number of endpoints for %q, expected %v, got %v", tt.value, len(tt.expected), len(eps))
+		}
+		for i := range eps {
+			if eps[i].String() != tt.expected[i] {
+				t.Fatalf("incorrect endpoint, expected %v, got %v", tt.expected[i], eps[i])
+			}
+		}
+	}
+	c := Collection{
+		{T: `endpoint`, Pkg: `packapi-sit20191024.103-0`, Key: `advisorxml`, Value: `wmax.srv.example.com:9030:http:srv1m7`},
+		{T: `endpoint`, Pkg: `packapi-sit20191024.103-0`, Key: `broken`, Value: `nope`},
+		{T: `parameter`, Pkg: `packapi-sit20191024.103-0`, Key: `ports__ENVOY_HTTP_PORT`, Value: `8000`},
+	}
+	eps, err := c.Endpoints()
+	errs, ok := err.(EndpointErrors)
+	switch {
+	case !ok || len(errs) != 1 || errs[0].Key != `broken`:
+		t.Fatalf("expected one endpoint error for key broken, got %v", err)
+	case len(eps) != 1 || eps[0].Port != 9030 || eps[0].Key != `advisorxml`:
+		t.Fatalf("unexpected endpoints: %+v", eps)
+	}
+	c.AssignADs(`default`)
+	if c[0].AppDomain != `srv1m7` || c[1].AppDomain != `default` || c[2].AppDomain != `default` {
+		t.Fatalf("incorrect appdomains assigned: %q, %q, %q", c[0].AppDomain, c[1].AppDomain, c[2].AppDomain)
+	}
+}
@@ -0,0 +1,155 @@
+package appconfig
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MaskRule hides expected differences when comparing environments.
+// A rule with no Value ignores matching keys entirely, otherwise matches of Value are replaced with Replace before comparing.
+type MaskRule struct {
+	// ENV limits the rule to a single env, empty applies to all envs.
+	ENV string
+	// Key selects the keys the rule applies to, nil applies to all keys.
+	Key     *regexp.Regexp
+	Value   *regexp.Regexp
+	Replace string
+}
+
+// IgnoreKeys returns a MaskRule ignoring the given keys in all envs.
+func IgnoreKeys(keys ...string) MaskRule {
+	quoted := make([]string, len(keys))
+	for i, k := range keys {
+		quoted[i] = regexp.QuoteMeta(k)
+	}
+	return MaskRule{
+		Key: regexp.MustCompile(`^(` + strings.Join(quoted, `|`) + `)$`),
+	}
+}
+
+func (m MaskRule) applies(env, key string) bool {
+	return (m.ENV == "" || m.ENV == env) && (m.Key == nil || m.Key.MatchString(key))
+}
+
+// ValueDiff is an app key whose values differ between two envs.
+// Each side holds the sorted unique effective values found across the env's nodes.
+type ValueDiff struct {
+	AppKey
+	A []string `json:"a"`
+	B []string `json:"b"`
+}
+
+// PkgDiff is an app whose newest pkg version differs between two envs.
+// An empty side means the app was not found in that env.
+type PkgDiff struct {
+	App string `json:"app"`
+	A   string `json:"a"`
+	B   string `json:"b"`
+}
+
+// EnvComparison is the result of comparing an ASI between two envs.
+type EnvComparison struct {
+	ASI     string      `json:"asi"`
+	EnvA    string      `json:"envA"`
+	EnvB    string      `json:"envB"`
+	OnlyInA []AppKey    `json:"onlyInA,omitempty"`
+	OnlyInB []AppKey    `json:"onlyInB,omitempty"`
+	Values  []ValueDiff `json:"values,omitempty"`
+	Pkgs    []PkgDiff   `json:"pkgs,omitempty"`
+}
+
+// IsEmpty returns true if no differences were found.
+func (e EnvComparison) IsEmpty() bool {
+	return len(e.OnlyInA) == 0 && len(e.OnlyInB) == 0 && len(e.Values) == 0 && len(e.Pkgs) == 0
+}
+
+// CompareEnvs compares the given ASI between two envs using DefaultPrecedence, after applying the given MaskRules.
+// Keys are compared per app, since the pkg versions deployed to each env usually differ.
+func (s SavedState) CompareEnvs(asi, envA, envB string, rules ...MaskRule) EnvComparison {
+	asiState := s.Filter(ASIIs(asi))
+	stateA, stateB := asiState.Filter(ENVIs(envA)), asiState.Filter(ENVIs(envB))
+	valsA, valsB := envValues(stateA, envA, rules), envValues(stateB, envB, rules)
+	cmp := EnvComparison{
+		ASI:  asi,
+		EnvA: envA,
+		EnvB: envB,
+	}
+	for key, a := range valsA {
+		b, ok := valsB[key]
+		switch {
+		case !ok:
+			cmp.OnlyInA = append(cmp.OnlyInA, key)
+		case strings.Join(a, "\x00") != strings.Join(b, "\x00"):
+			cmp.Values = append(cmp.Values, ValueDiff{AppKey: key, A: a, B: b})
+		}
+	}
+	for key := range valsB {
+		if _, ok := valsA[key]; !ok {
+			cmp.OnlyInB = append(cmp.OnlyInB, key)
+		}
+	}
+	sortAppKeys(cmp.OnlyInA)
+	sortAppKeys(cmp.OnlyInB)
+	sort.Slice(cmp.Values, func(i, j int) bool { return cmp.Values[i].less(cmp.Values[j].AppKey) })
+	pkgsA, pkgsB := stateA.NewestPkgs(), stateB.NewestPkgs()
+	for app, a := range pkgsA {
+		if b, ok := pkgsB[app]; !ok || a.Version() != b.Version() {
+			cmp.Pkgs = append(cmp.Pkgs, PkgDiff{App: app, A: a.Version(), B: pkgVersion(b, ok)})
+		}
+	}
+	for app, b := range pkgsB {
+		if _, ok := pkgsA[app]; !ok {
+			cmp.Pkgs = append(cmp.Pkgs, PkgDiff{App: app, B: b.Version()})
+		}
+	}
+	sort.Slice(cmp.Pkgs, func(i, j int) bool { return cmp.Pkgs[i].App < cmp.Pkgs[j].App })
+	return cmp
+}
+
+func pkgVersion(ref PkgRef, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return ref.Version()
+}
+
+// envValues returns the sorted unique masked effective values of each app key across the SavedFiles.
+func envValues(s SavedState, env string, rules []MaskRule) map[AppKey][]string {
+	sets := make(map[AppKey]map[string]bool)
+	for _, sf := range s {
+		for _, res := range sf.Collection().Resolve(DefaultPrecedence) {
+			val, ok := maskValue(rules, env, res.Key, res.Winner.Value)
+			if !ok {
+				continue
+			}
+			k := res.AppKey()
+			if sets[k] == nil {
+				sets[k] = make(map[string]bool)
+			}
+			sets[k][val] = true
+		}
+	}
+	vals := make(map[AppKey][]string, len(sets))
+	for key, set := range sets {
+		for v := range set {
+			vals[key] = append(vals[key], v)
+		}
+		sort.Strings(vals[key])
+	}
+	return vals
+}
+
+// maskValue applies the MaskRules to a value, returning false if the key is ignored.
+func maskValue(rules []MaskRule, env, key, val string) (string, bool) {
+	for _, r := range rules {
+		if !r.applies(env, key) {
+			continue
+		}
+		if r.Value == nil {
+			return "", false
+		}
+		val = r.Value.ReplaceAllString(val, r.Replace)
+	}
+	return val, true
+}
@@ -0,0 +1,47 @@
+package appconfig
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestCompareEnvs(t *testing.T) {
+	file := func(env, node, pkg string, c Collection) SavedFile {
+		for i := range c {
+			c[i].Pkg = pkg
+		}
+		return testFile(env, node, 0, c...)
+	}
+	SS := SavedState{
+		file(`sit`, `a`, `packapi-sit20191024.103-0`, Collection{
+			{Key: `node`, Value: `a`},
+			{Key: `db_host`, Value: `sit-db.example.com`},
+			{Key: `ports__ENVOY_HTTP_PORT`, Value: `8000`},
+			{Key: `debug`, Value: `true`},
+		}),
+		file(`prd`, `b`, `packapi-prd20191020.99-0`, Collection{
+			{Key: `node`, Value: `b`},
+			{Key: `db_host`, Value: `prd-db.example.com`},
+			{Key: `ports__ENVOY_HTTP_PORT`, Value: `8080`},
+			{Key: `replicas`, Value: `3`},
+		}),
+	}
+	rules := []MaskRule{
+		IgnoreKeys(DefaultNodeKeys...),
+		{Key: regexp.MustCompile(`_host$`), Value: regexp.MustCompile(`^(sit|prd)-`)},
+	}
+	cmp := SS.CompareEnvs(`wm:app:packapi`, `sit`, `prd`, rules...)
+	switch {
+	case len(cmp.OnlyInA) != 1 || cmp.OnlyInA[0].Key != `debug`:
+		t.Fatalf("unexpected keys only in sit: %v", cmp.OnlyInA)
+	case len(cmp.OnlyInB) != 1 || cmp.OnlyInB[0].Key != `replicas`:
+		t.Fatalf("unexpected keys only in prd: %v", cmp.OnlyInB)
+	case len(cmp.Values) != 1 || cmp.Values[0].App != `packapi` || cmp.Values[0].Key != `ports__ENVOY_HTTP_PORT`:
+		t.Fatalf("unexpected value differences: %+v", cmp.Values)
+	case len(cmp.Pkgs) != 1 || cmp.Pkgs[0].A != `sit20191024.103-0` || cmp.Pkgs[0].B != `prd20191020.99-0`:
+		t.Fatalf("unexpected pkg differences: %+v", cmp.Pkgs)
+	}
+	if unmasked := SS.CompareEnvs(`wm:app:packapi`, `sit`, `prd`); len(unmasked.Values) != 3 {
+		t.Fatalf("incorrect number of unmasked value differences, expected %v, got %v", 3, len(unmasked.Values))
+	}
+}
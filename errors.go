@@ -0,0 +1,121 @@
+package appconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseErrorKind defines the kind of problem found while parsing a StateFile.
+type ParseErrorKind int
+
+func (k ParseErrorKind) String() string {
+	return ParseErrorKindString[k]
+}
+
+// ParseErrorKinds Defined:
+const (
+	ErrKindEmptyMessage ParseErrorKind = iota // 0
+	ErrKindBadJSON
+	ErrKindUnknownType
+	ErrKindMissingKey
+	ErrKindMissingPkg
+	ErrKindInvalidDttm
+)
+
+// ParseErrorKindString enables a way to identify a ParseErrorKind with a string.
+var ParseErrorKindString = [...]string{
+	ErrKindEmptyMessage: "empty message",
+	ErrKindBadJSON:      "bad json",
+	ErrKindUnknownType:  "unknown data type",
+	ErrKindMissingKey:   "missing key",
+	ErrKindMissingPkg:   "missing pkg",
+	ErrKindInvalidDttm:  "invalid dttm",
+}
+
+// ParseError is a single problem found while parsing a StateFile.
+// Index and Key identify the offending Data entry, Index is -1 when the problem is not tied to an entry.
+type ParseError struct {
+	Kind   ParseErrorKind
+	Index  int
+	Key    string
+	Detail string
+}
+
+func (e *ParseError) Error() string {
+	var msg string
+	switch {
+	case e.Index < 0:
+		msg = e.Kind.String()
+	default:
+		msg = fmt.Sprintf("entry %d (key %q): %v", e.Index, e.Key, e.Kind)
+	}
+	if e.Detail != "" {
+		msg += `: ` + e.Detail
+	}
+	return msg
+}
+
+// ParseErrors is the report of all problems found while parsing a StateFile.
+type ParseErrors []*ParseError
+
+func (p ParseErrors) Error() string {
+	msgs := make([]string, len(p))
+	for i, e := range p {
+		msgs[i] = e.Error()
+	}
+	return fmt.Sprintf("%d error(s) parsing statefile: %s", len(p), strings.Join(msgs, `; `))
+}
+
+// HasKind returns true if any of the errors are of the given ParseErrorKind.
+func (p ParseErrors) HasKind(kind ParseErrorKind) bool {
+	for _, e := range p {
+		if e.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// ValueErrorKind defines the kind of problem found while looking up a typed value.
+type ValueErrorKind int
+
+func (k ValueErrorKind) String() string {
+	return ValueErrorKindString[k]
+}
+
+// ValueErrorKinds Defined:
+const (
+	ValueMissing ValueErrorKind = iota // 0
+	ValueAmbiguous
+	ValueInvalid
+)
+
+// ValueErrorKindString enables a way to identify a ValueErrorKind with a string.
+var ValueErrorKindString = [...]string{
+	ValueMissing:   "missing",
+	ValueAmbiguous: "ambiguous",
+	ValueInvalid:   "invalid",
+}
+
+// ValueError is returned by the typed value lookups on a Collection.
+type ValueError struct {
+	Kind   ValueErrorKind
+	Pkg    string
+	Key    string
+	Values []string
+	Err    error
+}
+
+func (e *ValueError) Error() string {
+	key := e.Key
+	if e.Pkg != "" {
+		key = e.Pkg + `/` + e.Key
+	}
+	switch e.Kind {
+	case ValueMissing:
+		return fmt.Sprintf("key %q: no value found", key)
+	case ValueAmbiguous:
+		return fmt.Sprintf("key %q: ambiguous, found %d values %q", key, len(e.Values), e.Values)
+	}
+	return fmt.Sprintf("key %q: invalid value %q: %v", key, firstValue(e.Values), e.Err)
+}
@@ -0,0 +1,140 @@
+package appconfig
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var _ Store = (*FileStore)(nil)
+
+// FileStore is a durable Store keeping one JSON file per EASIN in a directory.
+// Writes go to a temporary file which is synced and renamed into place.
+type FileStore struct {
+	mu  sync.RWMutex
+	dir string
+}
+
+// NewFileStore returns a new FileStore using the given directory, creating it if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{
+		dir: dir,
+	}, nil
+}
+
+func (f *FileStore) path(easin string) string {
+	return filepath.Join(f.dir, fmt.Sprintf("%x.json", sha1.Sum([]byte(easin))))
+}
+
+// Put stores the SavedFile, replacing any SavedFile with the same EASIN.
+func (f *FileStore) Put(savedFile SavedFile) error {
+	b, err := json.Marshal(savedFile)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tmp, err := ioutil.TempFile(f.dir, `.tmp-`)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), f.path(savedFile.EASIN)); err != nil {
+		return err
+	}
+	return f.syncDir()
+}
+
+// syncDir flushes the directory entry for a rename, failures on platforms without directory sync are ignored.
+func (f *FileStore) syncDir() error {
+	d, err := os.Open(f.dir)
+	if err != nil {
+		return err
+	}
+	d.Sync()
+	return d.Close()
+}
+
+// Get returns the SavedFile for the EASIN, or ErrNotFound.
+func (f *FileStore) Get(easin string) (SavedFile, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.read(f.path(easin))
+}
+
+// List returns the SavedFiles matching all the given FilePredicates, sorted by EASIN.
+func (f *FileStore) List(filters ...FilePredicate) (SavedState, error) {
+	state, err := f.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return state.Filter(FileAnd(filters...)), nil
+}
+
+// Delete removes the SavedFile for the EASIN, or returns ErrNotFound.
+func (f *FileStore) Delete(easin string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err := os.Remove(f.path(easin))
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Snapshot returns every stored SavedFile, sorted by EASIN.
+func (f *FileStore) Snapshot() (SavedState, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	entries, err := ioutil.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+	var state SavedState
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), `.`) || filepath.Ext(e.Name()) != `.json` {
+			continue
+		}
+		sf, err := f.read(filepath.Join(f.dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		state = append(state, sf)
+	}
+	sortByEASIN(state)
+	return state, nil
+}
+
+func (f *FileStore) read(path string) (savedFile SavedFile, err error) {
+	b, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return savedFile, ErrNotFound
+	case err != nil:
+		return
+	}
+	err = json.Unmarshal(b, &savedFile)
+	if err != nil {
+		return savedFile, fmt.Errorf("%s: %v", path, err)
+	}
+	return
+}
@@ -0,0 +1,131 @@
+package appconfig
+
+import (
+	"sort"
+	"time"
+)
+
+// History keeps a time-ordered series of SavedFiles per EASIN, ordered by StateFile.Time().
+// History is not safe for concurrent use.
+type History struct {
+	series map[string][]SavedFile
+}
+
+// NewHistory returns a new History seeded with the given SavedFiles.
+func NewHistory(savedFiles ...SavedFile) *History {
+	h := &History{
+		series: make(map[string][]SavedFile),
+	}
+	for _, sf := range savedFiles {
+		h.Add(sf)
+	}
+	return h
+}
+
+// Add inserts the SavedFile into its EASIN's series, replacing any SavedFile with the same Dttm.
+func (h *History) Add(savedFile SavedFile) {
+	series := h.series[savedFile.EASIN]
+	t := savedFile.StateFile.Time()
+	i := sort.Search(len(series), func(i int) bool {
+		return !series[i].StateFile.Time().Before(t)
+	})
+	switch {
+	case i < len(series) && series[i].StateFile.Dttm == savedFile.StateFile.Dttm:
+		series[i] = savedFile
+	default:
+		series = append(series, SavedFile{})
+		copy(series[i+1:], series[i:])
+		series[i] = savedFile
+	}
+	h.series[savedFile.EASIN] = series
+}
+
+// EASINs returns the sorted EASINs found in the History.
+func (h *History) EASINs() []string {
+	easins := make([]string, 0, len(h.series))
+	for easin := range h.series {
+		easins = append(easins, easin)
+	}
+	sort.Strings(easins)
+	return easins
+}
+
+// Series returns the time-ordered SavedFiles for the given EASIN.
+func (h *History) Series(easin string) []SavedFile {
+	return h.series[easin]
+}
+
+// Latest returns the most recent SavedFile for the given EASIN.
+func (h *History) Latest(easin string) (SavedFile, bool) {
+	series := h.series[easin]
+	if len(series) < 1 {
+		return SavedFile{}, false
+	}
+	return series[len(series)-1], true
+}
+
+// AsOf returns the SavedFile in effect for the given EASIN at time t, the latest one at or before t.
+func (h *History) AsOf(easin string, t time.Time) (SavedFile, bool) {
+	series := h.series[easin]
+	i := sort.Search(len(series), func(i int) bool {
+		return series[i].StateFile.Time().After(t)
+	})
+	if i == 0 {
+		return SavedFile{}, false
+	}
+	return series[i-1], true
+}
+
+// StateAsOf returns the SavedState in effect across all EASINs at time t.
+func (h *History) StateAsOf(t time.Time) SavedState {
+	var state SavedState
+	for _, easin := range h.EASINs() {
+		if sf, ok := h.AsOf(easin, t); ok {
+			state = append(state, sf)
+		}
+	}
+	return state
+}
+
+// Range returns the SavedFiles for the given EASIN whose time falls between from and to, inclusive.
+func (h *History) Range(easin string, from, to time.Time) []SavedFile {
+	var files []SavedFile
+	for _, sf := range h.series[easin] {
+		t := sf.StateFile.Time()
+		if !t.Before(from) && !t.After(to) {
+			files = append(files, sf)
+		}
+	}
+	return files
+}
+
+// KeyChanges returns the times the effective value of the given key changed for the EASIN, using DefaultPrecedence.
+// The key is resolved per app, so a new pkg version holding the same value is not a change.
+// The key appearing or disappearing in any app counts as a change.
+func (h *History) KeyChanges(easin, key string) []time.Time {
+	var times []time.Time
+	var prev map[string]string
+	for i, sf := range h.series[easin] {
+		vals := make(map[string]string)
+		for _, res := range sf.Collection().FromKey(key).Resolve(DefaultPrecedence) {
+			vals[pkgApp(res.Pkg)] = res.Winner.Value
+		}
+		if (i == 0 && len(vals) > 0) || (i > 0 && !equalStringMaps(prev, vals)) {
+			times = append(times, sf.StateFile.Time())
+		}
+		prev = vals
+	}
+	return times
+}
+
+func equalStringMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+package appconfig
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	file := func(dttm float64, port string) SavedFile {
+		if port == "" {
+			return testFile(`srv`, `srv24w0m15`, dttm)
+		}
+		return testFile(`srv`, `srv24w0m15`, dttm, Data{Key: `ports__ENVOY_HTTP_PORT`, Src: `default`, Value: port})
+	}
+	h := NewHistory(file(300, `8001`), file(100, `8000`), file(200, `8000`), file(400, ``), file(200, `8000`))
+	easin := `srv:wm:app:packapi:srv24w0m15`
+	at := func(secs int64) time.Time { return time.Unix(secs, 0) }
+	if n := len(h.Series(easin)); n != 4 {
+		t.Fatalf("incorrect series length, expected %v, got %v", 4, n)
+	}
+	sf, ok := h.AsOf(easin, at(250))
+	switch {
+	case !ok || sf.StateFile.Dttm != 200:
+		t.Fatalf("unexpected statefile as of 250: %+v", sf)
+	case len(h.StateAsOf(at(250))) != 1:
+		t.Fatalf("expected one saved file in state as of 250")
+	}
+	if _, ok := h.AsOf(easin, at(50)); ok {
+		t.Fatalf("expected no statefile before the first")
+	}
+	if n := len(h.Range(easin, at(200), at(300))); n != 2 {
+		t.Fatalf("incorrect range length, expected %v, got %v", 2, n)
+	}
+	changes := h.KeyChanges(easin, `ports__ENVOY_HTTP_PORT`)
+	if len(changes) != 3 || !changes[0].Equal(at(100)) || !changes[1].Equal(at(300)) || !changes[2].Equal(at(400)) {
+		t.Fatalf("unexpected key changes: %v", changes)
+	}
+	upgraded := NewHistory(
+		testFile(`srv`, `srv24w0m15`, 100, Data{Key: `timezone`, Pkg: `packapi-sit20191024.103-0`, Value: `EDT`}),
+		testFile(`srv`, `srv24w0m15`, 200, Data{Key: `timezone`, Pkg: `packapi-sit20191025.104-0`, Value: `EDT`}),
+	)
+	if n := len(upgraded.KeyChanges(easin, `timezone`)); n != 1 {
+		t.Fatalf("incorrect number of key changes across pkg versions, expected %v, got %v", 1, n)
+	}
+}
@@ -0,0 +1,165 @@
+package appconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Identity is the normalized identity of an application instance.
+// The EASI is the ENV and ASI joined, such as srv:wm:app:packapi, and the EASIN adds the Node.
+type Identity struct {
+	ENV  string `json:"env"`
+	ASI  string `json:"asi"`
+	Node string `json:"node"`
+}
+
+// ParseIdentity parses an EASI in either its colon (srv:wm:app:packapi) or dash (srv-wm-app-packapi) form.
+// An EASI containing a colon is always treated as the colon form, so dashes inside its parts are kept.
+func ParseIdentity(easi string) (id Identity, err error) {
+	easi = strings.TrimSpace(easi)
+	if !strings.Contains(easi, `:`) {
+		easi = dashToColon(easi)
+	}
+	parts := strings.Split(easi, `:`)
+	if len(parts) < 2 {
+		return id, fmt.Errorf("invalid easi %q", easi)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return id, fmt.Errorf("invalid easi %q", easi)
+		}
+	}
+	id.ENV, id.ASI = parts[0], strings.Join(parts[1:], `:`)
+	return id, nil
+}
+
+// ParseEASIN parses an EASIN, an EASI followed by a colon and the node.
+func ParseEASIN(easin string) (id Identity, err error) {
+	i := strings.LastIndex(easin, `:`)
+	if i < 0 || i == len(easin)-1 {
+		return id, fmt.Errorf("invalid easin %q", easin)
+	}
+	id, err = ParseIdentity(easin[:i])
+	if err != nil {
+		return id, fmt.Errorf("invalid easin %q", easin)
+	}
+	id.Node = easin[i+1:]
+	return id, nil
+}
+
+// EASI returns the EASI in its colon form.
+func (i Identity) EASI() string {
+	return i.ENV + `:` + i.ASI
+}
+
+// DashEASI returns the EASI in its dash form, as found in state files and source paths.
+func (i Identity) DashEASI() string {
+	return strings.Replace(i.EASI(), `:`, `-`, -1)
+}
+
+// EASIN returns the EASIN.
+func (i Identity) EASIN() string {
+	return i.EASI() + `:` + i.Node
+}
+
+// String returns the EASIN if the Node is known, otherwise the EASI.
+func (i Identity) String() string {
+	if i.Node == "" {
+		return i.EASI()
+	}
+	return i.EASIN()
+}
+
+// IsZero returns true if the Identity is empty.
+func (i Identity) IsZero() bool {
+	return i == Identity{}
+}
+
+// Identity returns the Identity found in the Collection's easi and node keys.
+func (c Collection) Identity() (id Identity, err error) {
+	simple := c.FromType(TypeSimple)
+	easis := filterUnique(simple.Get(`easi`))
+	nodes := filterUnique(simple.Get(`node`))
+	switch {
+	case len(easis) != 1:
+		return id, fmt.Errorf("expected 1 easi value, found %d", len(easis))
+	case len(nodes) > 1:
+		return id, fmt.Errorf("expected 1 node value, found %d", len(nodes))
+	}
+	id, err = ParseIdentity(easis[0])
+	if err != nil {
+		return
+	}
+	id.Node = firstValue(nodes)
+	return id, nil
+}
+
+// Identity returns the Identity given by the SavedFile's envelope.
+func (s *SavedFile) Identity() Identity {
+	id, err := ParseIdentity(s.EASI)
+	if err != nil {
+		id = Identity{ENV: s.ENV, ASI: s.ASI}
+	}
+	id.Node = s.Node
+	return id
+}
+
+// IdentityMismatch is a single field whose value differs between a SavedFile's envelope and its Collection.
+// The envelope env and envelope asi fields report an envelope inconsistent with itself,
+// Envelope then holds its ENV or ASI field and Collection the part found in its EASI.
+type IdentityMismatch struct {
+	Field      string `json:"field"`
+	Envelope   string `json:"envelope"`
+	Collection string `json:"collection"`
+}
+
+// IdentityError reports the differences between a SavedFile's envelope identity and the identity inside its Collection.
+type IdentityError struct {
+	EASIN      string
+	Mismatches []IdentityMismatch
+	Err        error
+}
+
+func (e *IdentityError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.EASIN, e.Err)
+	}
+	msgs := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		msgs[i] = fmt.Sprintf("%s %q != %q", m.Field, m.Envelope, m.Collection)
+	}
+	return fmt.Sprintf("%s: identity mismatch: %s", e.EASIN, strings.Join(msgs, `, `))
+}
+
+// CheckIdentity compares the SavedFile's envelope identity against the identity inside its Collection,
+// and the envelope's own ENV and ASI fields against its EASI.
+// A *IdentityError is returned if the Collection identity is missing or any part differs.
+func (s *SavedFile) CheckIdentity() error {
+	cid, err := s.Collection().Identity()
+	if err != nil {
+		return &IdentityError{EASIN: s.EASIN, Err: err}
+	}
+	eid := s.Identity()
+	var mm []IdentityMismatch
+	check := func(field, env, col string) {
+		if env != col {
+			mm = append(mm, IdentityMismatch{Field: field, Envelope: env, Collection: col})
+		}
+	}
+	check(`env`, eid.ENV, cid.ENV)
+	check(`asi`, eid.ASI, cid.ASI)
+	check(`node`, eid.Node, cid.Node)
+	check(`envelope env`, s.ENV, eid.ENV)
+	check(`envelope asi`, s.ASI, eid.ASI)
+	if len(mm) > 0 {
+		return &IdentityError{EASIN: s.EASIN, Mismatches: mm}
+	}
+	return nil
+}
+
+// dashToColon converts a dash form EASI to its colon form.
+// Dashes inside names cannot be told apart from separators, so the dash form is taken to be the env followed by a
+// three part ASI, as in srv-wm-app-packapi, and any further dashes are kept in the last part.
+func dashToColon(easi string) string {
+	return strings.Replace(easi, `-`, `:`, 3)
+}
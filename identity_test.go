@@ -0,0 +1,57 @@
+package appconfig
+
+import "testing"
+
+func TestIdentity(t *testing.T) {
+	colon, err := ParseIdentity(`srv:wm:app:packapi`)
+	if err != nil {
+		t.Fatalf("error parsing easi: %v", err)
+	}
+	dash, err := ParseIdentity(`srv-wm-app-packapi`)
+	switch {
+	case err != nil:
+		t.Fatalf("error parsing easi: %v", err)
+	case colon != dash:
+		t.Fatalf("expected identities to match, got %v and %v", colon, dash)
+	case colon.ENV != `srv` || colon.ASI != `wm:app:packapi` || colon.DashEASI() != `srv-wm-app-packapi`:
+		t.Fatalf("incorrect identity: %+v", colon)
+	}
+	for _, easi := range []string{`srv:wm:app:pack-api`, `srv-wm-app-pack-api`} {
+		id, err := ParseIdentity(easi)
+		if err != nil || id.ENV != `srv` || id.ASI != `wm:app:pack-api` {
+			t.Fatalf("incorrect identity for %q: %+v, %v", easi, id, err)
+		}
+	}
+	id, err := ParseEASIN(`srv:wm:app:packapi:srv24w0m15`)
+	if err != nil || id.Node != `srv24w0m15` || id.EASI() != colon.EASI() {
+		t.Fatalf("incorrect easin identity: %+v, %v", id, err)
+	}
+	sf := testSavedFile(t)
+	if err := sf.CheckIdentity(); err != nil {
+		t.Fatalf("unexpected identity error: %v", err)
+	}
+	dashed := sf
+	dashed.ASI, dashed.EASI, dashed.StateFile.Collection = `wm:app:pack-api`, `srv:wm:app:pack-api`, Collection{
+		{T: `simple`, Key: `easi`, Value: `srv-wm-app-pack-api`},
+		{T: `simple`, Key: `node`, Value: sf.Node},
+	}
+	if err := dashed.CheckIdentity(); err != nil {
+		t.Fatalf("unexpected identity error for app name with a dash: %v", err)
+	}
+	sf.Node = `srv99w0m15`
+	err = sf.CheckIdentity()
+	idErr, ok := err.(*IdentityError)
+	if !ok || len(idErr.Mismatches) != 1 || idErr.Mismatches[0].Field != `node` {
+		t.Fatalf("expected node mismatch, got %v", err)
+	}
+	inconsistent := testSavedFile(t)
+	inconsistent.ENV = `prd`
+	err = inconsistent.CheckIdentity()
+	idErr, ok = err.(*IdentityError)
+	switch {
+	case !ok || len(idErr.Mismatches) != 1:
+		t.Fatalf("expected a single envelope env mismatch, got %v", err)
+	case idErr.Mismatches[0] != IdentityMismatch{Field: `envelope env`, Envelope: `prd`, Collection: `srv`}:
+		t.Fatalf("unexpected envelope env mismatch: %+v", idErr.Mismatches[0])
+	}
+}
@@ -0,0 +1,227 @@
+package appconfig
+
+// Index is a read-only view of a Collection with hash indexes on key, pkg, appdomain, type, src and template.
+// Lookups return the same results, in the same order, as the equivalent Collection methods.
+type Index struct {
+	data   Collection
+	byKey  fieldIndex
+	byPkg  fieldIndex
+	byAD   fieldIndex
+	byT    fieldIndex
+	bySrc  fieldIndex
+	byTpl  fieldIndex
+	byType map[DataType][]int
+	values fieldIndex
+}
+
+// fieldIndex maps a field value to the positions of the data holding it, keeping the order values were first seen.
+type fieldIndex struct {
+	pos   map[string][]int
+	order []string
+}
+
+// values returns a copy of the values in the order they were first seen.
+func (f *fieldIndex) values() []string {
+	if len(f.order) < 1 {
+		return nil
+	}
+	vals := make([]string, len(f.order))
+	copy(vals, f.order)
+	return vals
+}
+
+func newFieldIndex() fieldIndex {
+	return fieldIndex{
+		pos: make(map[string][]int),
+	}
+}
+
+func (f *fieldIndex) add(val string, i int) {
+	p, ok := f.pos[val]
+	if !ok {
+		f.order = append(f.order, val)
+	}
+	f.pos[val] = append(p, i)
+}
+
+// NewIndex returns a new Index built from the given Collection.
+// The Collection must not be modified while the Index is in use.
+func NewIndex(c Collection) *Index {
+	idx := &Index{
+		data:   c,
+		byKey:  newFieldIndex(),
+		byPkg:  newFieldIndex(),
+		byAD:   newFieldIndex(),
+		byT:    newFieldIndex(),
+		bySrc:  newFieldIndex(),
+		byTpl:  newFieldIndex(),
+		byType: make(map[DataType][]int),
+		values: newFieldIndex(),
+	}
+	for i := 0; i < len(c); i++ {
+		d := &c[i]
+		idx.byKey.add(d.Key, i)
+		idx.byPkg.add(d.Pkg, i)
+		idx.byAD.add(d.AppDomain, i)
+		idx.byT.add(d.T, i)
+		idx.bySrc.add(d.Src, i)
+		idx.values.add(d.Value, i)
+		dupe := make(map[string]bool, len(d.Tpls))
+		for _, tpl := range d.Tpls {
+			if !dupe[tpl] {
+				dupe[tpl] = true
+				idx.byTpl.add(tpl, i)
+			}
+		}
+		dt := d.DataType()
+		idx.byType[dt] = append(idx.byType[dt], i)
+	}
+	return idx
+}
+
+// Index returns an Index built from the Collection.
+func (c Collection) Index() *Index {
+	return NewIndex(c)
+}
+
+// Index returns an Index built from the aggregated Collection of the SavedState.
+func (s SavedState) Index() *Index {
+	return NewIndex(s.Collection())
+}
+
+// Len returns the number of indexed data.
+func (x *Index) Len() int {
+	return len(x.data)
+}
+
+// Collection returns a copy of the underlying Collection.
+func (x *Index) Collection() Collection {
+	data := make(Collection, len(x.data))
+	for i, d := range x.data {
+		data[i] = copyData(d)
+	}
+	return data
+}
+
+func (x *Index) collect(pos []int) Collection {
+	if len(pos) < 1 {
+		return nil
+	}
+	data := make(Collection, len(pos))
+	for i, p := range pos {
+		data[i] = copyData(x.data[p])
+	}
+	return data
+}
+
+// copyData returns a copy of the Data that does not share its Tpls.
+func copyData(d Data) Data {
+	if d.Tpls != nil {
+		d.Tpls = append([]string(nil), d.Tpls...)
+	}
+	return d
+}
+
+// Keys returns all the keys found in the Index.
+func (x *Index) Keys() []string {
+	return x.byKey.values()
+}
+
+// Values returns all the values found in the Index.
+func (x *Index) Values() []string {
+	return x.values.values()
+}
+
+// Pkgs returns all the pkgs found in the Index.
+func (x *Index) Pkgs() []string {
+	return x.byPkg.values()
+}
+
+// ADs returns all the AppDomains found in the Index.
+func (x *Index) ADs() []string {
+	return x.byAD.values()
+}
+
+// Types returns all the types found in the Index.
+func (x *Index) Types() []string {
+	return x.byT.values()
+}
+
+// Srcs returns all the srcs found in the Index.
+func (x *Index) Srcs() []string {
+	return x.bySrc.values()
+}
+
+// Tpls returns all the templates found in the Index.
+func (x *Index) Tpls() []string {
+	return x.byTpl.values()
+}
+
+// HasType returns true if the given DataType is present in the Index.
+func (x *Index) HasType(dt DataType) bool {
+	return len(x.byType[dt]) > 0
+}
+
+// FromType returns a sub Collection containing only the specified DataType.
+func (x *Index) FromType(dt DataType) Collection {
+	return x.collect(x.byType[dt])
+}
+
+// FromPkg returns a sub Collection containing only the data with the given pkg.
+func (x *Index) FromPkg(pkg string) Collection {
+	return x.collect(x.byPkg.pos[pkg])
+}
+
+// FromKey returns a sub Collection containing only the data with the given key.
+func (x *Index) FromKey(key string) Collection {
+	return x.collect(x.byKey.pos[key])
+}
+
+// FromAD returns a sub Collection containing only the data with the given AppDomain.
+func (x *Index) FromAD(ad string) Collection {
+	return x.collect(x.byAD.pos[ad])
+}
+
+// FromSrc returns a sub Collection containing only the data with the given src.
+func (x *Index) FromSrc(src string) Collection {
+	return x.collect(x.bySrc.pos[src])
+}
+
+// FromTpl returns a sub Collection containing only the data feeding the given template.
+func (x *Index) FromTpl(tpl string) Collection {
+	return x.collect(x.byTpl.pos[tpl])
+}
+
+// Filter returns a sub Collection containing only the data matching the given Predicate.
+// Filter is not indexed and scans all data.
+func (x *Index) Filter(p Predicate) Collection {
+	return x.data.Filter(p)
+}
+
+// Get returns all the values found for the given key.
+func (x *Index) Get(key string) (values []string) {
+	for _, p := range x.byKey.pos[key] {
+		values = append(values, x.data[p].Value)
+	}
+	return
+}
+
+// GetFromPkg returns all the values found for the given pkg and key.
+func (x *Index) GetFromPkg(pkg, key string) (values []string) {
+	byKey, byPkg := x.byKey.pos[key], x.byPkg.pos[pkg]
+	switch {
+	case len(byKey) <= len(byPkg):
+		for _, p := range byKey {
+			if x.data[p].HasPkg(pkg) {
+				values = append(values, x.data[p].Value)
+			}
+		}
+	default:
+		for _, p := range byPkg {
+			if x.data[p].HasKey(key) {
+				values = append(values, x.data[p].Value)
+			}
+		}
+	}
+	return
+}
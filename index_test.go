@@ -0,0 +1,50 @@
+package appconfig
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	sf := testSavedFile(t)
+	SS := SavedState{sf, sf}
+	c := SS.Collection()
+	idx := SS.Index()
+	pkg := `packapi-sit20191024.103-0`
+	checks := []struct {
+		name     string
+		expected interface{}
+		got      interface{}
+	}{
+		{`keys`, c.Keys(), idx.Keys()},
+		{`values`, c.Values(), idx.Values()},
+		{`pkgs`, c.Pkgs(), idx.Pkgs()},
+		{`ads`, c.ADs(), idx.ADs()},
+		{`types`, c.Types(), idx.Types()},
+		{`get`, c.Get(`node`), idx.Get(`node`)},
+		{`getFromPkg`, c.GetFromPkg(pkg, `ports__ENVOY_HTTP_PORT`), idx.GetFromPkg(pkg, `ports__ENVOY_HTTP_PORT`)},
+		{`fromType`, c.FromType(TypeParameter), idx.FromType(TypeParameter)},
+		{`fromPkg`, c.FromPkg(pkg), idx.FromPkg(pkg)},
+		{`fromKey`, c.FromKey(`easi`), idx.FromKey(`easi`)},
+		{`fromAD`, c.FromAD(`srv1m7`), idx.FromAD(`srv1m7`)},
+		{`fromSrc`, c.Filter(SrcIs(`facter`)), idx.FromSrc(`facter`)},
+		{`fromTpl`, c.Filter(TplIs(`config/envoy.yaml`)), idx.FromTpl(`config/envoy.yaml`)},
+		{`missing`, c.FromKey(`nope`), idx.FromKey(`nope`)},
+	}
+	for _, chk := range checks {
+		if !reflect.DeepEqual(chk.expected, chk.got) {
+			t.Fatalf("index %v mismatch, expected %v, got %v", chk.name, chk.expected, chk.got)
+		}
+	}
+	keys := idx.Keys()
+	sort.Strings(keys)
+	idx.Collection()[0].Key = `changed`
+	if !reflect.DeepEqual(c.Keys(), idx.Keys()) || idx.FromKey(`changed`) != nil {
+		t.Fatalf("expected the index to be unaffected by changes to returned slices")
+	}
+	idx.FromTpl(`config/envoy.yaml`)[0].Tpls[0] = `changed`
+	if len(idx.Collection().Filter(TplIs(`changed`))) != 0 {
+		t.Fatalf("expected the index to be unaffected by changes to returned tpls")
+	}
+}
@@ -0,0 +1,133 @@
+package appconfig
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// KeySeparator separates the namespaces of a parameter key, such as ports__ADVISOR_HTTP_PORT.
+const KeySeparator = `__`
+
+// TreeValueKey is the map key used for the value of a KeyTree node that also has children.
+const TreeValueKey = `_value`
+
+// KeyTree is a hierarchical view of a Collection, built by splitting keys on KeySeparator.
+type KeyTree struct {
+	Name     string
+	Data     Collection
+	Children map[string]*KeyTree
+}
+
+// KeyTree returns the KeyTree for the Collection.
+func (c Collection) KeyTree() *KeyTree {
+	root := newKeyTree("")
+	for _, d := range c {
+		node := root
+		for _, name := range splitKey(d.Key) {
+			child, ok := node.Children[name]
+			if !ok {
+				child = newKeyTree(name)
+				node.Children[name] = child
+			}
+			node = child
+		}
+		node.Data = append(node.Data, d)
+	}
+	return root
+}
+
+// FromKeyPrefix returns a sub Collection containing only the data whose key is, or is namespaced under, the given prefix.
+// The prefix may contain several namespaces, such as endpoint__advisorxml.
+func (c Collection) FromKeyPrefix(prefix string) Collection {
+	var data []Data
+	for _, d := range c {
+		if d.Key == prefix || strings.HasPrefix(d.Key, prefix+KeySeparator) {
+			data = append(data, d)
+		}
+	}
+	return data
+}
+
+func newKeyTree(name string) *KeyTree {
+	return &KeyTree{
+		Name:     name,
+		Children: make(map[string]*KeyTree),
+	}
+}
+
+func splitKey(key string) []string {
+	return strings.Split(key, KeySeparator)
+}
+
+// Get returns the node found by following the given path of names, or nil if it does not exist.
+// A single name containing KeySeparator is split into its namespaces.
+func (t *KeyTree) Get(path ...string) *KeyTree {
+	node := t
+	for _, p := range path {
+		for _, name := range splitKey(p) {
+			child, ok := node.Children[name]
+			if !ok {
+				return nil
+			}
+			node = child
+		}
+	}
+	return node
+}
+
+// Names returns the sorted names of the node's children.
+func (t *KeyTree) Names() []string {
+	names := make([]string, 0, len(t.Children))
+	for name := range t.Children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsLeaf returns true if the node has no children.
+func (t *KeyTree) IsLeaf() bool {
+	return len(t.Children) == 0
+}
+
+// Collection returns all the data found at and below the node.
+func (t *KeyTree) Collection() Collection {
+	data := append(Collection{}, t.Data...)
+	for _, name := range t.Names() {
+		data = append(data, t.Children[name].Collection()...)
+	}
+	return data
+}
+
+// Map returns the node as nested maps.
+// Leaves hold their value as a string, or a []string if more than one unique value exists.
+func (t *KeyTree) Map() map[string]interface{} {
+	m := make(map[string]interface{}, len(t.Children))
+	for name, child := range t.Children {
+		switch {
+		case child.IsLeaf():
+			m[name] = child.value()
+		default:
+			cm := child.Map()
+			if len(child.Data) > 0 {
+				cm[TreeValueKey] = child.value()
+			}
+			m[name] = cm
+		}
+	}
+	return m
+}
+
+// MarshalJSON returns the node as nested JSON objects.
+func (t *KeyTree) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Map())
+}
+
+func (t *KeyTree) value() interface{} {
+	vals := t.Data.Values()
+	if len(vals) == 1 {
+		return vals[0]
+	}
+	return vals
+}
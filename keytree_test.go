@@ -0,0 +1,29 @@
+package appconfig
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestKeyTree(t *testing.T) {
+	stateFile := testSavedFile(t).StateFile
+	tree := stateFile.KeyTree()
+	ports := tree.Get(`ports`)
+	if ports == nil || len(ports.Children) != 8 || len(ports.Collection()) != len(stateFile.FromKeyPrefix(`ports`)) {
+		t.Fatalf("unexpected ports node: %+v", ports)
+	}
+	advisor := tree.Get(`endpoint__advisorxml`)
+	if advisor == nil || len(advisor.Names()) != 2 || advisor.Names()[0] != `endpoint` || advisor.Names()[1] != `port` {
+		t.Fatalf("unexpected advisorxml node: %+v", advisor)
+	}
+	b, err := json.Marshal(advisor)
+	if err != nil {
+		t.Fatalf("error marshaling tree: %v", err)
+	}
+	if string(b) != `{"endpoint":"wmax.srv.example.com","port":"9030"}` {
+		t.Fatalf("unexpected json: %s", b)
+	}
+	if tree.Get(`ports`, `NOPE`) != nil {
+		t.Fatalf("expected nil for missing path")
+	}
+}
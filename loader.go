@@ -0,0 +1,158 @@
+package appconfig
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// StateFileName is the name appconfig gives to the state file it writes on install.
+const StateFileName = `appconfig-install.state.json`
+
+// FileMeta is the identity metadata applied to a SavedFile loaded from disk.
+// Empty fields are derived from the path and the StateFile contents where possible.
+// EASI, if set, takes precedence over ENV and ASI.
+type FileMeta struct {
+	ENV        string
+	ASI        string
+	EASI       string
+	Node       string
+	Datacenter string
+	Workgroup  string
+}
+
+// LoadStateFile reads and returns the StateFile at the given path.
+func LoadStateFile(path string) (stateFile StateFile, err error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+	return parseStateFile(b)
+}
+
+// LoadSavedFile reads the StateFile at the given path and returns it as a SavedFile.
+func LoadSavedFile(path string, meta FileMeta) (savedFile SavedFile, err error) {
+	stateFile, err := LoadStateFile(path)
+	if err != nil {
+		return
+	}
+	return newSavedFile(filepath.ToSlash(path), stateFile, meta)
+}
+
+// LoadSavedState walks the tree at root and returns a SavedState built from every state file found.
+// The given metadata is applied to every SavedFile.
+func LoadSavedState(root string, meta FileMeta) (state SavedState, err error) {
+	err = filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() != StateFileName {
+			return nil
+		}
+		sf, err := LoadSavedFile(p, meta)
+		if err != nil {
+			return fmt.Errorf("%s: %v", p, err)
+		}
+		state = append(state, sf)
+		return nil
+	})
+	return
+}
+
+// LoadSavedStateTar reads a tar archive, optionally gzip compressed, and returns a SavedState built from every state file found.
+// The given metadata is applied to every SavedFile.
+func LoadSavedStateTar(r io.Reader, meta FileMeta) (state SavedState, err error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil {
+		return
+	}
+	var tr *tar.Reader
+	switch {
+	case magic[0] == 0x1f && magic[1] == 0x8b:
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return state, err
+		}
+		defer gz.Close()
+		tr = tar.NewReader(gz)
+	default:
+		tr = tar.NewReader(br)
+	}
+	for {
+		hdr, err := tr.Next()
+		switch {
+		case err == io.EOF:
+			return state, nil
+		case err != nil:
+			return state, err
+		}
+		if hdr.Typeflag != tar.TypeReg || path.Base(hdr.Name) != StateFileName {
+			continue
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return state, fmt.Errorf("%s: %v", hdr.Name, err)
+		}
+		stateFile, err := parseStateFile(b)
+		if err != nil {
+			return state, fmt.Errorf("%s: %v", hdr.Name, err)
+		}
+		sf, err := newSavedFile(path.Join(`/`, hdr.Name), stateFile, meta)
+		if err != nil {
+			return state, fmt.Errorf("%s: %v", hdr.Name, err)
+		}
+		state = append(state, sf)
+	}
+}
+
+// newSavedFile returns the StateFile as a SavedFile, resolving its ENV and ASI before building the EASI from them.
+func newSavedFile(source string, stateFile StateFile, meta FileMeta) (SavedFile, error) {
+	id, err := metaIdentity(source, stateFile, meta)
+	if err != nil {
+		return SavedFile{}, err
+	}
+	savedFile := SavedFile{
+		ENV:        id.ENV,
+		ASI:        id.ASI,
+		EASI:       id.EASI(),
+		Node:       firstNonEmpty(meta.Node, firstValue(stateFile.FromType(TypeSimple).Get(`node`))),
+		Datacenter: meta.Datacenter,
+		Workgroup:  meta.Workgroup,
+		Source:     source,
+		StateFile:  stateFile,
+	}
+	savedFile.EASIN = savedFile.EASI + `:` + savedFile.Node
+	return savedFile, nil
+}
+
+// metaIdentity returns the Identity given by the FileMeta.
+// An EASI in the FileMeta is used as is, otherwise the ENV and ASI each fall back to the source path, then the StateFile contents.
+func metaIdentity(source string, stateFile StateFile, meta FileMeta) (Identity, error) {
+	if meta.EASI != "" {
+		return ParseIdentity(meta.EASI)
+	}
+	id, err := sourceIdentity(source)
+	if err != nil {
+		id, _ = stateFile.Identity()
+	}
+	return Identity{
+		ENV: firstNonEmpty(meta.ENV, id.ENV),
+		ASI: firstNonEmpty(meta.ASI, id.ASI),
+	}, nil
+}
+
+// sourceIdentity derives the Identity from a state file path laid out as .../<easi>/logs/appconfig-install.state.json.
+func sourceIdentity(source string) (Identity, error) {
+	dir := path.Dir(source)
+	if path.Base(dir) != `logs` {
+		return Identity{}, fmt.Errorf("unexpected source layout %q", source)
+	}
+	return ParseIdentity(path.Base(path.Dir(dir)))
+}
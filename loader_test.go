@@ -0,0 +1,76 @@
+package appconfig
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSavedState(t *testing.T) {
+	kMsg := testKafkaMSG(t)
+	root, err := ioutil.TempDir("", "appconfig")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, `example`, `srv-wm-app-packapi`, `logs`)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("error creating state file dir: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, StateFileName), []byte(kMsg.Message), 0644); err != nil {
+		t.Fatalf("error writing state file: %v", err)
+	}
+	state, err := LoadSavedState(root, FileMeta{Datacenter: `m15`})
+	if err != nil {
+		t.Fatalf("error loading saved state: %v", err)
+	}
+	if len(state) != 1 {
+		t.Fatalf("incorrect number of saved files, expected %v, got %v", 1, len(state))
+	}
+	sf := state[0]
+	switch {
+	case sf.ENV != kMsg.ENV || sf.ASI != kMsg.ASI || sf.EASI != kMsg.EASI:
+		t.Fatalf("incorrect identity, got %q %q %q", sf.ENV, sf.ASI, sf.EASI)
+	case !sf.HasNode(kMsg.Node) || !sf.HasDatacenter(`m15`):
+		t.Fatalf("incorrect node or datacenter, got %q %q", sf.Node, sf.Datacenter)
+	case sf.SHA() != kMsg.SHA():
+		t.Fatalf("sha1 values for savedfile do not match kafka message, expected %v received %v", kMsg.SHA(), sf.SHA())
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	name := `example/srv-wm-app-packapi/logs/` + StateFileName
+	tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(kMsg.Message)), Typeflag: tar.TypeReg})
+	tw.Write([]byte(kMsg.Message))
+	tw.Close()
+	state, err = LoadSavedStateTar(&buf, FileMeta{})
+	switch {
+	case err != nil:
+		t.Fatalf("error loading tar: %v", err)
+	case len(state) != 1 || state[0].EASIN != kMsg.EASIN():
+		t.Fatalf("unexpected saved state from tar: %+v", state)
+	case state[0].Source != `/`+name:
+		t.Fatalf("incorrect source, got %q", state[0].Source)
+	}
+
+	sf, err = LoadSavedFile(filepath.Join(dir, StateFileName), FileMeta{ENV: `prd`})
+	switch {
+	case err != nil:
+		t.Fatalf("error loading saved file: %v", err)
+	case sf.ENV != `prd` || sf.ASI != kMsg.ASI || sf.EASI != `prd:`+kMsg.ASI:
+		t.Fatalf("incorrect identity with env meta, got %q %q %q", sf.ENV, sf.ASI, sf.EASI)
+	}
+	sf, err = LoadSavedFile(filepath.Join(dir, StateFileName), FileMeta{ENV: `prd`, EASI: `dev:wm:app:other`})
+	switch {
+	case err != nil:
+		t.Fatalf("error loading saved file: %v", err)
+	case sf.ENV != `dev` || sf.ASI != `wm:app:other` || sf.EASI != `dev:wm:app:other`:
+		t.Fatalf("incorrect identity with easi meta, got %q %q %q", sf.ENV, sf.ASI, sf.EASI)
+	}
+	if _, err := LoadSavedFile(filepath.Join(dir, StateFileName), FileMeta{EASI: `srv`}); err == nil {
+		t.Fatalf("expected an error loading with an invalid easi meta")
+	}
+}
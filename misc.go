@@ -2,23 +2,6 @@ package appconfig
 
 import "strings"
 
-func epAD(ep string) string {
-	var val string
-	switch {
-	case !strings.Contains(ep, `'`):
-		return ""
-	default:
-		tmp := strings.Split(ep, `'`)
-		for _, t := range tmp {
-			x := strings.Split(t, `:`)
-			if len(x) > 1 {
-				val = x[len(x)-1]
-			}
-		}
-	}
-	return val
-}
-
 func adSplit(ad string) string {
 	ads := strings.Split(ad, `,`)
 	if len(ads) > 0 {
@@ -38,3 +21,19 @@ func filterUnique(vals []string) []string {
 	}
 	return tmp
 }
+
+func firstValue(vals []string) string {
+	if len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,162 @@
+package appconfig
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var pkgRegex = regexp.MustCompile(`^(.+)-([a-zA-Z]*)(\d{8})\.(\d+)-(\d+)$`)
+
+// PkgRef is a parsed pkg value in the form <app>-<channel><date>.<build>-<revision>, such as packapi-sit20191024.103-0.
+type PkgRef struct {
+	Pkg      string    `json:"pkg"`
+	App      string    `json:"app"`
+	Channel  string    `json:"channel"`
+	Date     time.Time `json:"date"`
+	Build    int       `json:"build"`
+	Revision int       `json:"revision"`
+}
+
+// ParsePkgRef parses the given pkg into a PkgRef.
+func ParsePkgRef(pkg string) (ref PkgRef, err error) {
+	m := pkgRegex.FindStringSubmatch(pkg)
+	if m == nil {
+		return ref, fmt.Errorf("invalid pkg %q", pkg)
+	}
+	date, err := time.Parse(`20060102`, m[3])
+	if err != nil {
+		return ref, fmt.Errorf("invalid pkg %q: bad build date %q", pkg, m[3])
+	}
+	build, err := strconv.Atoi(m[4])
+	if err != nil {
+		return ref, fmt.Errorf("invalid pkg %q: bad build number %q", pkg, m[4])
+	}
+	rev, err := strconv.Atoi(m[5])
+	if err != nil {
+		return ref, fmt.Errorf("invalid pkg %q: bad revision %q", pkg, m[5])
+	}
+	ref = PkgRef{
+		Pkg:      pkg,
+		App:      m[1],
+		Channel:  m[2],
+		Date:     date,
+		Build:    build,
+		Revision: rev,
+	}
+	return ref, nil
+}
+
+// pkgApp returns the app of the given pkg, or the pkg itself if it cannot be parsed.
+func pkgApp(pkg string) string {
+	ref, err := ParsePkgRef(pkg)
+	if err != nil {
+		return pkg
+	}
+	return ref.App
+}
+
+// String returns the original pkg value.
+func (p PkgRef) String() string {
+	return p.Pkg
+}
+
+// Version returns the version portion of the pkg, such as sit20191024.103-0.
+func (p PkgRef) Version() string {
+	return p.Pkg[len(p.App)+1:]
+}
+
+// Less returns true if the PkgRef is older than the given PkgRef.
+func (p PkgRef) Less(o PkgRef) bool {
+	return ComparePkgRefs(p, o) < 0
+}
+
+// ComparePkgRefs orders two PkgRefs by build date, build number and revision, then channel.
+// The result is -1 if a is older than b, 1 if a is newer and 0 if they are the same version.
+func ComparePkgRefs(a, b PkgRef) int {
+	switch {
+	case a.Date.Before(b.Date):
+		return -1
+	case a.Date.After(b.Date):
+		return 1
+	case a.Build != b.Build:
+		return compareInts(a.Build, b.Build)
+	case a.Revision != b.Revision:
+		return compareInts(a.Revision, b.Revision)
+	case a.Channel < b.Channel:
+		return -1
+	case a.Channel > b.Channel:
+		return 1
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// PkgRefs returns the parsed PkgRefs for all pkgs found in the Collection.
+// Pkgs that cannot be parsed are skipped.
+func (c Collection) PkgRefs() (refs []PkgRef) {
+	for _, pkg := range c.Pkgs() {
+		ref, err := ParsePkgRef(pkg)
+		if err == nil {
+			refs = append(refs, ref)
+		}
+	}
+	return
+}
+
+// NewestPkgs returns the newest PkgRef per app found in the Collection.
+func (c Collection) NewestPkgs() map[string]PkgRef {
+	return pickPkgRefs(c.PkgRefs(), 1)
+}
+
+// OldestPkgs returns the oldest PkgRef per app found in the Collection.
+func (c Collection) OldestPkgs() map[string]PkgRef {
+	return pickPkgRefs(c.PkgRefs(), -1)
+}
+
+// NewestPkgs returns the newest PkgRef per app found in the SavedState.
+func (s SavedState) NewestPkgs() map[string]PkgRef {
+	return pickPkgRefs(s.Collection().PkgRefs(), 1)
+}
+
+// OldestPkgs returns the oldest PkgRef per app found in the SavedState.
+func (s SavedState) OldestPkgs() map[string]PkgRef {
+	return pickPkgRefs(s.Collection().PkgRefs(), -1)
+}
+
+// Behind returns the SavedFiles whose newest pkg for the given app is older than the newest found in the SavedState.
+func (s SavedState) Behind(app string) SavedState {
+	newest, ok := s.NewestPkgs()[app]
+	if !ok {
+		return nil
+	}
+	var state SavedState
+	for _, sf := range s {
+		ref, ok := sf.Collection().NewestPkgs()[app]
+		if ok && ref.Less(newest) {
+			state = append(state, sf)
+		}
+	}
+	return state
+}
+
+func pickPkgRefs(refs []PkgRef, want int) map[string]PkgRef {
+	picked := make(map[string]PkgRef)
+	for _, ref := range refs {
+		cur, ok := picked[ref.App]
+		if !ok || ComparePkgRefs(ref, cur) == want {
+			picked[ref.App] = ref
+		}
+	}
+	return picked
+}
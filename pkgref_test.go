@@ -0,0 +1,42 @@
+package appconfig
+
+import "testing"
+
+func TestPkgRef(t *testing.T) {
+	ref, err := ParsePkgRef(`packapi-sit20191024.103-0`)
+	switch {
+	case err != nil:
+		t.Fatalf("error parsing pkg: %v", err)
+	case ref.App != `packapi` || ref.Channel != `sit` || ref.Date.Format(`20060102`) != `20191024` || ref.Build != 103 || ref.Revision != 0:
+		t.Fatalf("incorrect pkg ref: %+v", ref)
+	case ref.Version() != `sit20191024.103-0`:
+		t.Fatalf("incorrect version, got %q", ref.Version())
+	}
+	if _, err := ParsePkgRef(`packapi`); err == nil {
+		t.Fatalf("expected error parsing invalid pkg")
+	}
+	ordered := []string{
+		`my-app-sit20191023.200-0`,
+		`my-app-sit20191024.9-0`,
+		`my-app-sit20191024.10-0`,
+		`my-app-sit20191024.10-1`,
+	}
+	for i := 1; i < len(ordered); i++ {
+		a, _ := ParsePkgRef(ordered[i-1])
+		b, _ := ParsePkgRef(ordered[i])
+		if !a.Less(b) || b.Less(a) {
+			t.Fatalf("expected %v to be older than %v", a, b)
+		}
+	}
+	SS := SavedState{
+		{EASIN: `a`, StateFile: StateFile{Collection: Collection{{Pkg: ordered[3]}}}},
+		{EASIN: `b`, StateFile: StateFile{Collection: Collection{{Pkg: ordered[1]}}}},
+	}
+	behind := SS.Behind(`my-app`)
+	switch {
+	case SS.NewestPkgs()[`my-app`].Pkg != ordered[3] || SS.OldestPkgs()[`my-app`].Pkg != ordered[1]:
+		t.Fatalf("incorrect newest or oldest pkgs")
+	case len(behind) != 1 || behind[0].EASIN != `b`:
+		t.Fatalf("expected node b to be behind, got %+v", behind)
+	}
+}
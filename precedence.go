@@ -0,0 +1,141 @@
+package appconfig
+
+import "sort"
+
+// Precedence orders srcs from lowest to highest priority.
+// Srcs not listed rank below all listed srcs.
+type Precedence []string
+
+// DefaultPrecedence is a default ordering of the srcs found in state files, from lowest to highest priority.
+var DefaultPrecedence = Precedence{
+	"default",
+	"facter",
+	"etmeta",
+	"environment",
+	"appconfig",
+}
+
+// Rank returns the priority of the given src, higher wins. Unlisted srcs return -1.
+func (p Precedence) Rank(src string) int {
+	for i, s := range p {
+		if s == src {
+			return i
+		}
+	}
+	return -1
+}
+
+// Resolution is the winning Data for a key along with the candidates it overrode.
+// Pkg is the pkg of the winning Data. Overridden is ordered from highest to lowest priority.
+type Resolution struct {
+	Pkg        string `json:"pkg"`
+	Key        string `json:"k"`
+	Winner     Data   `json:"winner"`
+	Overridden []Data `json:"overridden,omitempty"`
+}
+
+// IsOverride returns true if the winner overrode a candidate holding a different value.
+func (r Resolution) IsOverride() bool {
+	for _, d := range r.Overridden {
+		if d.Value != r.Winner.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolutions is a list of Resolution.
+type Resolutions []Resolution
+
+// Collection returns a Collection holding only the winning Data of each Resolution.
+func (r Resolutions) Collection() Collection {
+	data := make(Collection, len(r))
+	for i, res := range r {
+		data[i] = res.Winner
+	}
+	return data
+}
+
+// Overrides returns the Resolutions where the winner overrode a different value.
+func (r Resolutions) Overrides() Resolutions {
+	var res Resolutions
+	for _, x := range r {
+		if x.IsOverride() {
+			res = append(res, x)
+		}
+	}
+	return res
+}
+
+// pkgKey identifies a key within a pkg.
+type pkgKey struct {
+	pkg string
+	key string
+}
+
+// Resolve returns the Resolution for each pkg and key in the Collection using the given Precedence, in the order they are first found.
+// The same key found in different pkgs is resolved separately.
+// When candidates share the same rank, the last one found wins.
+func (c Collection) Resolve(p Precedence) Resolutions {
+	var keys []pkgKey
+	candidates := make(map[pkgKey][]Data)
+	for _, d := range c {
+		k := pkgKey{pkg: d.Pkg, key: d.Key}
+		if _, ok := candidates[k]; !ok {
+			keys = append(keys, k)
+		}
+		candidates[k] = append(candidates[k], d)
+	}
+	resolutions := make(Resolutions, len(keys))
+	for i, k := range keys {
+		resolutions[i] = resolve(k.key, candidates[k], p)
+	}
+	return resolutions
+}
+
+// Effective returns the Resolution for the given key across all pkgs using the given Precedence.
+// Use EffectiveFromPkg when the key may be found in more than one pkg.
+// False is returned if the key is not found.
+func (c Collection) Effective(key string, p Precedence) (Resolution, bool) {
+	candidates := c.FromKey(key)
+	if len(candidates) < 1 {
+		return Resolution{}, false
+	}
+	return resolve(key, candidates, p), true
+}
+
+// EffectiveFromPkg returns the Resolution for the given key within the given pkg using the given Precedence.
+// False is returned if the key is not found in the pkg.
+func (c Collection) EffectiveFromPkg(pkg, key string, p Precedence) (Resolution, bool) {
+	var candidates []Data
+	for _, d := range c {
+		if d.HasPkg(pkg) && d.HasKey(key) {
+			candidates = append(candidates, d)
+		}
+	}
+	if len(candidates) < 1 {
+		return Resolution{}, false
+	}
+	return resolve(key, candidates, p), true
+}
+
+func resolve(key string, candidates []Data, p Precedence) Resolution {
+	ordered := make([]Data, len(candidates))
+	copy(ordered, candidates)
+	// reversed so the stable sort keeps the last candidate found first among equal ranks.
+	for i, j := 0, len(ordered)-1; i < j; i, j = i+1, j-1 {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return p.Rank(ordered[i].Src) > p.Rank(ordered[j].Src)
+	})
+	res := Resolution{
+		Pkg:    ordered[0].Pkg,
+		Key:    key,
+		Winner: ordered[0],
+	}
+	if len(ordered) > 1 {
+		res.Overridden = ordered[1:]
+	}
+	return res
+}
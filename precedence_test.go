@@ -0,0 +1,48 @@
+package appconfig
+
+import "testing"
+
+func TestResolve(t *testing.T) {
+	c := Collection{
+		{Key: `ports__ENVOY_HTTP_PORT`, Src: `default`, Value: `8080`},
+		{Key: `ports__ENVOY_HTTP_PORT`, Src: `appconfig`, Value: `8000`},
+		{Key: `ports__ENVOY_HTTP_PORT`, Src: `environment`, Value: `8001`},
+		{Key: `timezone`, Src: `facter`, Value: `EDT`},
+		{Key: `node`, Src: `unknown`, Value: `a`},
+		{Key: `node`, Src: `unknown`, Value: `b`},
+		{Pkg: `envoy-sit20191024.7-0`, Key: `ports__ENVOY_HTTP_PORT`, Src: `default`, Value: `9000`},
+	}
+	res, ok := c.EffectiveFromPkg(``, `ports__ENVOY_HTTP_PORT`, DefaultPrecedence)
+	switch {
+	case !ok:
+		t.Fatalf("expected key to be found")
+	case res.Winner.Src != `appconfig` || res.Winner.Value != `8000`:
+		t.Fatalf("incorrect winner: %+v", res.Winner)
+	case len(res.Overridden) != 2 || res.Overridden[0].Src != `environment` || res.Overridden[1].Src != `default`:
+		t.Fatalf("incorrect overridden candidates: %+v", res.Overridden)
+	case !res.IsOverride():
+		t.Fatalf("expected resolution to be an override")
+	}
+	all := c.Resolve(DefaultPrecedence)
+	switch {
+	case len(all) != 4:
+		t.Fatalf("incorrect number of resolutions, expected %v, got %v", 4, len(all))
+	case all[3].Pkg != `envoy-sit20191024.7-0` || all[3].Winner.Value != `9000`:
+		t.Fatalf("expected the key in another pkg to resolve separately, got %+v", all[3])
+	case all[2].Winner.Value != `b`:
+		t.Fatalf("expected last candidate to win a tie, got %+v", all[2].Winner)
+	case len(all.Overrides()) != 2:
+		t.Fatalf("incorrect number of overrides, expected %v, got %v", 2, len(all.Overrides()))
+	case len(all.Collection()) != 4:
+		t.Fatalf("incorrect effective collection size")
+	}
+	if _, ok := c.EffectiveFromPkg(`envoy-sit20191024.7-0`, `timezone`, DefaultPrecedence); ok {
+		t.Fatalf("expected key missing from the pkg not to resolve")
+	}
+	if res, ok := c.Effective(`ports__ENVOY_HTTP_PORT`, DefaultPrecedence); !ok || res.Pkg != `` || len(res.Overridden) != 3 {
+		t.Fatalf("expected the key to resolve across pkgs, got %+v", res)
+	}
+	if _, ok := c.Effective(`nope`, DefaultPrecedence); ok {
+		t.Fatalf("expected missing key not to resolve")
+	}
+}
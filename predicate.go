@@ -0,0 +1,265 @@
+package appconfig
+
+import "regexp"
+
+// Predicate reports whether the given Data matches.
+type Predicate func(*Data) bool
+
+// FilePredicate reports whether the given SavedFile matches.
+type FilePredicate func(*SavedFile) bool
+
+// Filter returns a sub Collection containing only the data matching the given Predicate.
+func (c Collection) Filter(p Predicate) Collection {
+	var data []Data
+	for i := 0; i < len(c); i++ {
+		if p(&c[i]) {
+			data = append(data, c[i])
+		}
+	}
+	return data
+}
+
+// Filter returns a sub SavedState containing only the SavedFiles matching the given FilePredicate.
+func (s SavedState) Filter(p FilePredicate) SavedState {
+	var state SavedState
+	for i := 0; i < len(s); i++ {
+		if p(&s[i]) {
+			state = append(state, s[i])
+		}
+	}
+	return state
+}
+
+// And returns a Predicate matching when all the given Predicates match.
+func And(ps ...Predicate) Predicate {
+	return func(d *Data) bool {
+		for _, p := range ps {
+			if !p(d) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a Predicate matching when any of the given Predicates match.
+func Or(ps ...Predicate) Predicate {
+	return func(d *Data) bool {
+		for _, p := range ps {
+			if p(d) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a Predicate matching when the given Predicate does not.
+func Not(p Predicate) Predicate {
+	return func(d *Data) bool {
+		return !p(d)
+	}
+}
+
+// FileAnd returns a FilePredicate matching when all the given FilePredicates match.
+func FileAnd(ps ...FilePredicate) FilePredicate {
+	return func(s *SavedFile) bool {
+		for _, p := range ps {
+			if !p(s) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// FileOr returns a FilePredicate matching when any of the given FilePredicates match.
+func FileOr(ps ...FilePredicate) FilePredicate {
+	return func(s *SavedFile) bool {
+		for _, p := range ps {
+			if p(s) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FileNot returns a FilePredicate matching when the given FilePredicate does not.
+func FileNot(p FilePredicate) FilePredicate {
+	return func(s *SavedFile) bool {
+		return !p(s)
+	}
+}
+
+// TypeIs matches data of the given DataType.
+func TypeIs(dt DataType) Predicate {
+	return func(d *Data) bool { return d.DataType() == dt }
+}
+
+// PkgIs matches data with the given pkg.
+func PkgIs(pkg string) Predicate {
+	return func(d *Data) bool { return d.HasPkg(pkg) }
+}
+
+// PkgMatches matches data whose pkg matches the given regexp.
+func PkgMatches(regex *regexp.Regexp) Predicate {
+	return func(d *Data) bool { return regex.MatchString(d.Pkg) }
+}
+
+// KeyIs matches data with the given key.
+func KeyIs(key string) Predicate {
+	return func(d *Data) bool { return d.HasKey(key) }
+}
+
+// KeyMatches matches data whose key matches the given regexp.
+func KeyMatches(regex *regexp.Regexp) Predicate {
+	return func(d *Data) bool { return regex.MatchString(d.Key) }
+}
+
+// ValueIs matches data with the given value.
+func ValueIs(value string) Predicate {
+	return func(d *Data) bool { return d.HasValue(value) }
+}
+
+// ValueMatches matches data whose value matches the given regexp.
+func ValueMatches(regex *regexp.Regexp) Predicate {
+	return func(d *Data) bool { return regex.MatchString(d.Value) }
+}
+
+// SrcIs matches data with the given src.
+func SrcIs(src string) Predicate {
+	return func(d *Data) bool { return d.Src == src }
+}
+
+// SrcMatches matches data whose src matches the given regexp.
+func SrcMatches(regex *regexp.Regexp) Predicate {
+	return func(d *Data) bool { return regex.MatchString(d.Src) }
+}
+
+// ADIs matches data with the given AppDomain.
+func ADIs(ad string) Predicate {
+	return func(d *Data) bool { return d.HasAD(ad) }
+}
+
+// ADMatches matches data whose AppDomain matches the given regexp.
+func ADMatches(regex *regexp.Regexp) Predicate {
+	return func(d *Data) bool { return regex.MatchString(d.AppDomain) }
+}
+
+// TplIs matches data feeding the given template.
+func TplIs(tpl string) Predicate {
+	return func(d *Data) bool {
+		for _, t := range d.Tpls {
+			if t == tpl {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// TplMatches matches data feeding any template matching the given regexp.
+func TplMatches(regex *regexp.Regexp) Predicate {
+	return func(d *Data) bool {
+		for _, t := range d.Tpls {
+			if regex.MatchString(t) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ENVIs matches SavedFiles with the given env.
+func ENVIs(env string) FilePredicate {
+	return func(s *SavedFile) bool { return s.HasENV(env) }
+}
+
+// ENVMatches matches SavedFiles whose env matches the given regexp.
+func ENVMatches(regex *regexp.Regexp) FilePredicate {
+	return func(s *SavedFile) bool { return regex.MatchString(s.ENV) }
+}
+
+// ASIIs matches SavedFiles with the given asi.
+func ASIIs(asi string) FilePredicate {
+	return func(s *SavedFile) bool { return s.HasASI(asi) }
+}
+
+// ASIMatches matches SavedFiles whose asi matches the given regexp.
+func ASIMatches(regex *regexp.Regexp) FilePredicate {
+	return func(s *SavedFile) bool { return regex.MatchString(s.ASI) }
+}
+
+// EASIIs matches SavedFiles with the given easi.
+func EASIIs(easi string) FilePredicate {
+	return func(s *SavedFile) bool { return s.HasEASI(easi) }
+}
+
+// EASIMatches matches SavedFiles whose easi matches the given regexp.
+func EASIMatches(regex *regexp.Regexp) FilePredicate {
+	return func(s *SavedFile) bool { return regex.MatchString(s.EASI) }
+}
+
+// NodeIs matches SavedFiles with the given node.
+func NodeIs(node string) FilePredicate {
+	return func(s *SavedFile) bool { return s.HasNode(node) }
+}
+
+// NodeMatches matches SavedFiles whose node matches the given regexp.
+func NodeMatches(regex *regexp.Regexp) FilePredicate {
+	return func(s *SavedFile) bool { return regex.MatchString(s.Node) }
+}
+
+// EASINIs matches SavedFiles with the given easin.
+func EASINIs(easin string) FilePredicate {
+	return func(s *SavedFile) bool { return s.HasEASIN(easin) }
+}
+
+// EASINMatches matches SavedFiles whose easin matches the given regexp.
+func EASINMatches(regex *regexp.Regexp) FilePredicate {
+	return func(s *SavedFile) bool { return regex.MatchString(s.EASIN) }
+}
+
+// DatacenterIs matches SavedFiles with the given datacenter.
+func DatacenterIs(dc string) FilePredicate {
+	return func(s *SavedFile) bool { return s.HasDatacenter(dc) }
+}
+
+// DatacenterMatches matches SavedFiles whose datacenter matches the given regexp.
+func DatacenterMatches(regex *regexp.Regexp) FilePredicate {
+	return func(s *SavedFile) bool { return regex.MatchString(s.Datacenter) }
+}
+
+// WorkgroupIs matches SavedFiles with the given workgroup.
+func WorkgroupIs(wg string) FilePredicate {
+	return func(s *SavedFile) bool { return s.HasWorkgroup(wg) }
+}
+
+// WorkgroupMatches matches SavedFiles whose workgroup matches the given regexp.
+func WorkgroupMatches(regex *regexp.Regexp) FilePredicate {
+	return func(s *SavedFile) bool { return regex.MatchString(s.Workgroup) }
+}
+
+// SourceIs matches SavedFiles with the given source path.
+func SourceIs(source string) FilePredicate {
+	return func(s *SavedFile) bool { return s.Source == source }
+}
+
+// SourceMatches matches SavedFiles whose source path matches the given regexp.
+func SourceMatches(regex *regexp.Regexp) FilePredicate {
+	return func(s *SavedFile) bool { return regex.MatchString(s.Source) }
+}
+
+// AnyData matches SavedFiles containing at least one Data matching the given Predicate.
+func AnyData(p Predicate) FilePredicate {
+	return func(s *SavedFile) bool {
+		c := s.Collection()
+		for i := 0; i < len(c); i++ {
+			if p(&c[i]) {
+				return true
+			}
+		}
+		return false
+	}
+}
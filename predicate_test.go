@@ -0,0 +1,27 @@
+package appconfig
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestPredicates(t *testing.T) {
+	sf := testSavedFile(t)
+	c := sf.Collection()
+	p := And(TypeIs(TypeParameter), SrcIs(`default`), KeyMatches(regexp.MustCompile(`^ports__`)))
+	q := MustParseQuery(`type=parameter AND src=default AND k=~^ports__`)
+	if a, b := c.Filter(p), c.FromQuery(q); len(a) != 7 || len(b) != len(a) {
+		t.Fatalf("expected predicate and query to match 7 entries, got %v and %v", len(a), len(b))
+	}
+	if n := len(c.Filter(Or(TplIs(`config/envoy.yaml`), Not(TypeIs(TypeParameter))))); n != 15 {
+		t.Fatalf("incorrect number of results, expected %v, got %v", 15, n)
+	}
+	SS := SavedState{sf, {ENV: `prd`, EASIN: `other`}}
+	matched := SS.Filter(FileAnd(ENVIs(`srv`), AnyData(And(KeyIs(`ports__ENVOY_HTTP_PORT`), ValueIs(`8000`)))))
+	if len(matched) != 1 || matched[0].EASIN != sf.EASIN {
+		t.Fatalf("unexpected saved files: %+v", matched)
+	}
+	if n := len(SS.Filter(FileNot(ENVIs(`srv`)))); n != 1 {
+		t.Fatalf("incorrect number of saved files, expected %v, got %v", 1, n)
+	}
+}
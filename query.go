@@ -0,0 +1,409 @@
+package appconfig
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SyntaxError is returned when a query cannot be parsed.
+// Pos is the byte offset into the query where the problem was found.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos, e.Msg)
+}
+
+// queryFields maps the field names accepted in a query to the data values they refer to.
+var queryFields = map[string]func(*Data) []string{
+	"type":      func(d *Data) []string { return []string{d.T} },
+	"t":         func(d *Data) []string { return []string{d.T} },
+	"pkg":       func(d *Data) []string { return []string{d.Pkg} },
+	"tpls":      func(d *Data) []string { return d.Tpls },
+	"tpl":       func(d *Data) []string { return d.Tpls },
+	"src":       func(d *Data) []string { return []string{d.Src} },
+	"k":         func(d *Data) []string { return []string{d.Key} },
+	"key":       func(d *Data) []string { return []string{d.Key} },
+	"v":         func(d *Data) []string { return []string{d.Value} },
+	"value":     func(d *Data) []string { return []string{d.Value} },
+	"appdomain": func(d *Data) []string { return []string{d.AppDomain} },
+	"ad":        func(d *Data) []string { return []string{d.AppDomain} },
+}
+
+// Query is a parsed query that can be evaluated against Data.
+//
+// A query is made of comparisons joined with AND, OR, NOT and parentheses, such as:
+//
+//	type=parameter AND src=default AND k=~^ports__
+//	tpls has config/envoy.yaml OR (src in (etmeta, facter) AND NOT v="")
+//
+// Supported operators are = != =~ (regexp) !~ (negated regexp), has and in (a list of values).
+// Values containing spaces or parentheses must be quoted with double or single quotes.
+// Double quoted values are unescaped like Go strings, single quoted values only unescape \' and keep any other backslash as is.
+// Fields holding several values, such as tpls, match if any of their values match.
+type Query struct {
+	src   string
+	match Predicate
+}
+
+// ParseQuery parses the given query.
+func ParseQuery(query string) (*Query, error) {
+	p := &queryParser{src: query}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return &Query{src: query, match: match}, nil
+}
+
+// MustParseQuery is like ParseQuery but panics if the query cannot be parsed.
+func MustParseQuery(query string) *Query {
+	q, err := ParseQuery(query)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// String returns the original query.
+func (q *Query) String() string {
+	return q.src
+}
+
+// Match returns true if the Data matches the query.
+func (q *Query) Match(d Data) bool {
+	return q.match(&d)
+}
+
+// Predicate returns the query as a Predicate.
+func (q *Query) Predicate() Predicate {
+	return q.match
+}
+
+// FromQuery returns a sub Collection containing only the data matching the given Query.
+func (c Collection) FromQuery(q *Query) Collection {
+	return c.Filter(q.match)
+}
+
+// Query parses the given query and returns a sub Collection containing only the matching data.
+func (c Collection) Query(query string) (Collection, error) {
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return c.FromQuery(q), nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return strconv.Quote(t.val)
+	}
+	return fmt.Sprintf("%q", t.val)
+}
+
+func (t token) isKeyword(kw string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.val, kw)
+}
+
+type queryParser struct {
+	src string
+	pos int
+	tok token
+}
+
+func (p *queryParser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Pos: p.tok.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// next advances to the next token.
+func (p *queryParser) next() error {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return nil
+	}
+	switch c := p.src[p.pos]; {
+	case c == '(':
+		p.pos++
+		p.tok = token{kind: tokLParen, val: `(`, pos: start}
+	case c == ')':
+		p.pos++
+		p.tok = token{kind: tokRParen, val: `)`, pos: start}
+	case c == ',':
+		p.pos++
+		p.tok = token{kind: tokComma, val: `,`, pos: start}
+	case c == '=' || c == '!':
+		op := string(c)
+		p.pos++
+		if p.pos < len(p.src) && (p.src[p.pos] == '=' || p.src[p.pos] == '~') {
+			op += string(p.src[p.pos])
+			p.pos++
+		}
+		p.tok = token{kind: tokOp, val: op, pos: start}
+	case c == '"' || c == '\'':
+		end := start + 1
+		for end < len(p.src) && p.src[end] != c {
+			if p.src[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(p.src) {
+			p.tok = token{kind: tokEOF, pos: start}
+			return &SyntaxError{Pos: start, Msg: "unterminated string"}
+		}
+		raw := p.src[start+1 : end]
+		p.pos = end + 1
+		var val string
+		switch c {
+		case '"':
+			s, err := strconv.Unquote(`"` + raw + `"`)
+			if err != nil {
+				return &SyntaxError{Pos: start, Msg: "invalid string: " + err.Error()}
+			}
+			val = s
+		default:
+			val = strings.Replace(raw, `\'`, `'`, -1)
+		}
+		p.tok = token{kind: tokString, val: val, pos: start}
+	default:
+		for p.pos < len(p.src) {
+			c := p.src[p.pos]
+			if unicode.IsSpace(rune(c)) || c == '(' || c == ')' || c == ',' || c == '=' || c == '!' || c == '"' || c == '\'' {
+				break
+			}
+			p.pos++
+		}
+		p.tok = token{kind: tokWord, val: p.src[start:p.pos], pos: start}
+	}
+	return nil
+}
+
+// nextValue advances to the next token, treating any unquoted run of characters up to whitespace, a comma or a parenthesis as a value.
+func (p *queryParser) nextValue() error {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos < len(p.src) && (p.src[p.pos] == '"' || p.src[p.pos] == '\'') {
+		return p.next()
+	}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if unicode.IsSpace(rune(c)) || c == '(' || c == ')' || c == ',' {
+			break
+		}
+		p.pos++
+	}
+	if start == p.pos {
+		return p.next()
+	}
+	p.tok = token{kind: tokWord, val: p.src[start:p.pos], pos: start}
+	return nil
+}
+
+func (p *queryParser) parseOr() (Predicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.isKeyword(`OR`) {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(d *Data) bool { return l(d) || right(d) }
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (Predicate, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.isKeyword(`AND`) {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(d *Data) bool { return l(d) && right(d) }
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseUnary() (Predicate, error) {
+	if p.tok.isKeyword(`NOT`) {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(d *Data) bool { return !inner(d) }, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (Predicate, error) {
+	switch p.tok.kind {
+	case tokLParen:
+		open := p.tok
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, &SyntaxError{Pos: open.pos, Msg: "unclosed parenthesis"}
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokWord:
+		return p.parseComparison()
+	case tokEOF:
+		return nil, p.errorf("unexpected end of query, expected a comparison")
+	}
+	return nil, p.errorf("unexpected %s, expected a field name", p.tok)
+}
+
+func (p *queryParser) parseComparison() (Predicate, error) {
+	field := p.tok
+	values, ok := queryFields[strings.ToLower(field.val)]
+	if !ok {
+		return nil, p.errorf("unknown field %q", field.val)
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	op := p.tok
+	switch {
+	case op.kind == tokOp:
+	case op.isKeyword(`has`), op.isKeyword(`in`):
+		op.val = strings.ToLower(op.val)
+	default:
+		return nil, p.errorf("unexpected %s, expected an operator after %q", op, field.val)
+	}
+	if op.val == `in` {
+		return p.parseIn(values)
+	}
+	if err := p.nextValue(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokWord && p.tok.kind != tokString {
+		return nil, p.errorf("unexpected %s, expected a value after %q", p.tok, op.val)
+	}
+	val := p.tok
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	switch op.val {
+	case `=`, `==`, `has`:
+		return anyValue(values, func(v string) bool { return v == val.val }), nil
+	case `!=`:
+		eq := anyValue(values, func(v string) bool { return v == val.val })
+		return func(d *Data) bool { return !eq(d) }, nil
+	case `=~`, `!~`:
+		regex, err := regexp.Compile(val.val)
+		if err != nil {
+			return nil, &SyntaxError{Pos: val.pos, Msg: fmt.Sprintf("invalid regexp: %v", err)}
+		}
+		match := anyValue(values, regex.MatchString)
+		if op.val == `!~` {
+			return func(d *Data) bool { return !match(d) }, nil
+		}
+		return match, nil
+	}
+	return nil, &SyntaxError{Pos: op.pos, Msg: fmt.Sprintf("unknown operator %q", op.val)}
+}
+
+func (p *queryParser) parseIn(values func(*Data) []string) (Predicate, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokLParen {
+		return nil, p.errorf("unexpected %s, expected ( after in", p.tok)
+	}
+	set := make(map[string]bool)
+	for {
+		if err := p.nextValue(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokWord && p.tok.kind != tokString {
+			return nil, p.errorf("unexpected %s, expected a value", p.tok)
+		}
+		set[p.tok.val] = true
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == tokRParen {
+			break
+		}
+		if p.tok.kind != tokComma {
+			return nil, p.errorf("unexpected %s, expected , or )", p.tok)
+		}
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	return anyValue(values, func(v string) bool { return set[v] }), nil
+}
+
+func anyValue(values func(*Data) []string, fn func(string) bool) Predicate {
+	return func(d *Data) bool {
+		for _, v := range values(d) {
+			if fn(v) {
+				return true
+			}
+		}
+		return false
+	}
+}
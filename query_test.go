@@ -0,0 +1,64 @@
+package appconfig
+
+import "testing"
+
+func TestQuery(t *testing.T) {
+	stateFile := testSavedFile(t).StateFile
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{`type=parameter AND src=default AND k=~^ports__`, 7},
+		{`tpls has config/envoy.yaml`, 5},
+		{`tpls has config/envoy.yaml AND NOT src=default`, 2},
+		{`src in (facter, "etmeta")`, 10},
+		{`(type=simple OR type=endpoint) AND v!=""`, 10},
+		{`k =~ "^endpoint__advisorxml__" and v !~ '^[0-9]+$'`, 1},
+		{`type != parameter`, 10},
+	}
+	for _, tt := range tests {
+		c, err := stateFile.Query(tt.query)
+		switch {
+		case err != nil:
+			t.Fatalf("error parsing %q: %v", tt.query, err)
+		case len(c) != tt.expected:
+			t.Fatalf("incorrect number of results for %q, expected %v, got %v", tt.query, tt.expected, len(c))
+		}
+	}
+	quoted := Collection{{Key: `a`, Value: `a'b`}, {Key: `b`, Value: `a\'b`}, {Key: `c`, Value: `1.5`}}
+	for query, key := range map[string]string{
+		`v='a\'b'`:   `a`,
+		`v="a'b"`:    `a`,
+		`v="a\\'b"`:  `b`,
+		`v=~'^\d\.'`: `c`,
+	} {
+		c, err := quoted.Query(query)
+		switch {
+		case err != nil:
+			t.Fatalf("error parsing %q: %v", query, err)
+		case len(c) != 1 || c[0].Key != key:
+			t.Fatalf("unexpected results for %q, expected key %v, got %+v", query, key, c)
+		}
+	}
+	bad := []struct {
+		query string
+		pos   int
+	}{
+		{`type=parameter AND`, 18},
+		{`nope=1`, 0},
+		{`(type=simple`, 0},
+		{`k=~(`, 3},
+		{`type parameter`, 5},
+		{`v="abc`, 2},
+	}
+	for _, tt := range bad {
+		_, err := ParseQuery(tt.query)
+		sErr, ok := err.(*SyntaxError)
+		switch {
+		case !ok:
+			t.Fatalf("expected *SyntaxError for %q, got %v", tt.query, err)
+		case sErr.Pos != tt.pos:
+			t.Fatalf("incorrect position for %q, expected %v, got %v: %v", tt.query, tt.pos, sErr.Pos, sErr)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package appconfig
+
+import "time"
+
+// Origin identifies the SavedFile a Data came from.
+type Origin struct {
+	ENV   string  `json:"env"`
+	ASI   string  `json:"asi"`
+	EASI  string  `json:"easi"`
+	Node  string  `json:"node"`
+	EASIN string  `json:"easin"`
+	Dttm  float64 `json:"dttm"`
+}
+
+// Time returns time.Time from the Origin's Dttm.
+func (o *Origin) Time() time.Time {
+	return timeFromFloat64(o.Dttm)
+}
+
+// Origin returns the Origin of the SavedFile's data.
+func (s *SavedFile) Origin() Origin {
+	return Origin{
+		ENV:   s.ENV,
+		ASI:   s.ASI,
+		EASI:  s.EASI,
+		Node:  s.Node,
+		EASIN: s.EASIN,
+		Dttm:  s.StateFile.Dttm,
+	}
+}
+
+// OriginRecord pairs a Data with the Origin it came from.
+type OriginRecord struct {
+	Origin
+	Data
+}
+
+// OriginRecords is a list of OriginRecord.
+type OriginRecords []OriginRecord
+
+// Records returns an OriginRecord for every Data in the SavedState.
+func (s SavedState) Records() OriginRecords {
+	return s.Search(func(*Data) bool { return true })
+}
+
+// Search returns an OriginRecord for every Data in the SavedState matching the given Predicate.
+func (s SavedState) Search(p Predicate) OriginRecords {
+	var records OriginRecords
+	for i := 0; i < len(s); i++ {
+		origin := s[i].Origin()
+		c := s[i].Collection()
+		for j := 0; j < len(c); j++ {
+			if p(&c[j]) {
+				records = append(records, OriginRecord{Origin: origin, Data: c[j]})
+			}
+		}
+	}
+	return records
+}
+
+// SearchQuery parses the given query and returns an OriginRecord for every matching Data in the SavedState.
+func (s SavedState) SearchQuery(query string) (OriginRecords, error) {
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.Search(q.Predicate()), nil
+}
+
+// Filter returns the OriginRecords whose Data matches the given Predicate.
+func (r OriginRecords) Filter(p Predicate) OriginRecords {
+	var records OriginRecords
+	for i := 0; i < len(r); i++ {
+		if p(&r[i].Data) {
+			records = append(records, r[i])
+		}
+	}
+	return records
+}
+
+// FilterOrigin returns the OriginRecords whose Origin matches the given function.
+func (r OriginRecords) FilterOrigin(fn func(*Origin) bool) OriginRecords {
+	var records OriginRecords
+	for i := 0; i < len(r); i++ {
+		if fn(&r[i].Origin) {
+			records = append(records, r[i])
+		}
+	}
+	return records
+}
+
+// Query parses the given query and returns the OriginRecords whose Data matches.
+func (r OriginRecords) Query(query string) (OriginRecords, error) {
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return r.Filter(q.Predicate()), nil
+}
+
+// Collection returns the Data of the OriginRecords, without their Origin.
+func (r OriginRecords) Collection() Collection {
+	data := make(Collection, len(r))
+	for i, rec := range r {
+		data[i] = rec.Data
+	}
+	return data
+}
+
+// EASINs returns all the EASINs found in the OriginRecords.
+func (r OriginRecords) EASINs() (easins []string) {
+	dupe := make(map[string]bool)
+	for _, rec := range r {
+		if !dupe[rec.EASIN] {
+			dupe[rec.EASIN] = true
+			easins = append(easins, rec.EASIN)
+		}
+	}
+	return
+}
+
+// GroupByEASIN returns the OriginRecords grouped by EASIN.
+func (r OriginRecords) GroupByEASIN() map[string]OriginRecords {
+	groups := make(map[string]OriginRecords)
+	for _, rec := range r {
+		groups[rec.EASIN] = append(groups[rec.EASIN], rec)
+	}
+	return groups
+}
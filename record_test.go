@@ -0,0 +1,28 @@
+package appconfig
+
+import "testing"
+
+func TestRecords(t *testing.T) {
+	sf := testSavedFile(t)
+	other := sf
+	other.Node, other.EASIN = `srv25w0m15`, sf.EASI+`:srv25w0m15`
+	SS := SavedState{sf, other}
+	if n := len(SS.Records()); n != 2*len(sf.Collection()) {
+		t.Fatalf("incorrect number of records, expected %v, got %v", 2*len(sf.Collection()), n)
+	}
+	records, err := SS.SearchQuery(`k=ports__ENVOY_HTTP_PORT AND v=8000`)
+	switch {
+	case err != nil:
+		t.Fatalf("error searching: %v", err)
+	case len(records) != 2 || len(records.EASINs()) != 2:
+		t.Fatalf("unexpected records: %+v", records)
+	case records[1].Node != `srv25w0m15` || records[1].ENV != `srv` || records[1].Key != `ports__ENVOY_HTTP_PORT`:
+		t.Fatalf("record missing origin: %+v", records[1])
+	case !records[0].Time().Equal(sf.StateFile.Time()):
+		t.Fatalf("incorrect record time: %v", records[0].Time())
+	}
+	byNode := records.FilterOrigin(func(o *Origin) bool { return o.Node == `srv24w0m15` })
+	if len(byNode) != 1 || len(records.GroupByEASIN()) != 2 {
+		t.Fatalf("unexpected origin filter results: %+v", byNode)
+	}
+}
@@ -0,0 +1,317 @@
+package appconfig
+
+import "sync"
+
+// EventType defines the type of change made to a Registry.
+type EventType int
+
+func (e EventType) String() string {
+	return EventTypeString[e]
+}
+
+// EventTypes Defined:
+const (
+	EventNodeAdded EventType = iota // 0
+	EventStateFileUpdated
+	EventNodeRemoved
+)
+
+// EventTypeString enables a way to identify an EventType with a string.
+var EventTypeString = [...]string{
+	EventNodeAdded:        "added",
+	EventStateFileUpdated: "updated",
+	EventNodeRemoved:      "removed",
+}
+
+// Event is a change made to a Registry.
+// Old is nil for EventNodeAdded, New is nil for EventNodeRemoved and Diff is only set for EventStateFileUpdated.
+type Event struct {
+	Type  EventType
+	EASIN string
+	Old   *SavedFile
+	New   *SavedFile
+	Diff  Diff
+}
+
+// EventFilter selects the Events delivered to a Subscription. Empty fields match everything.
+type EventFilter struct {
+	ENV string
+	ASI string
+	// Keys matches updates whose Diff touches any of the keys, and added or removed nodes holding any of the keys.
+	Keys []string
+}
+
+func (f EventFilter) match(e *Event) bool {
+	sf := e.New
+	if sf == nil {
+		sf = e.Old
+	}
+	switch {
+	case f.ENV != "" && !sf.HasENV(f.ENV):
+		return false
+	case f.ASI != "" && !sf.HasASI(f.ASI):
+		return false
+	case len(f.Keys) == 0:
+		return true
+	}
+	for _, key := range f.Keys {
+		switch e.Type {
+		case EventStateFileUpdated:
+			for _, changes := range [][]Change{e.Diff.Added, e.Diff.Removed, e.Diff.Changed} {
+				for _, c := range changes {
+					if c.Key == key {
+						return true
+					}
+				}
+			}
+		default:
+			if len(sf.Collection().FromKey(key)) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var _ Store = (*Registry)(nil)
+
+// Registry is a Store, backed by a StateStore, which publishes changes to subscribers.
+// Reads go straight to the StateStore, so they are never blocked by publishing changes.
+type Registry struct {
+	store *StateStore
+	// mu serializes changes so Events are published in the order changes are made, it also guards subs and closed.
+	mu     sync.Mutex
+	subs   map[*Subscription]bool
+	closed bool
+}
+
+// NewRegistry returns a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		store: NewStateStore(),
+		subs:  make(map[*Subscription]bool),
+	}
+}
+
+// Put stores the SavedFile, replacing any SavedFile with the same EASIN.
+// Subscribers are sent an EventNodeAdded or, if the StateFile changed, an EventStateFileUpdated.
+func (r *Registry) Put(savedFile SavedFile) error {
+	r.put(savedFile, false)
+	return nil
+}
+
+// Upsert stores the SavedFile like Put, unless a SavedFile with the same EASIN and a newer StateFile.Dttm is already stored.
+// It returns true if the SavedFile was stored.
+func (r *Registry) Upsert(savedFile SavedFile) bool {
+	return r.put(savedFile, true)
+}
+
+func (r *Registry) put(savedFile SavedFile, upsert bool) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, err := r.store.Get(savedFile.EASIN)
+	found := err == nil
+	switch {
+	case upsert:
+		if !r.store.Upsert(savedFile) {
+			return false
+		}
+	default:
+		r.store.Put(savedFile)
+	}
+	if len(r.subs) == 0 {
+		return true
+	}
+	sf := savedFile
+	switch {
+	case !found:
+		r.publish(Event{Type: EventNodeAdded, EASIN: sf.EASIN, New: &sf})
+	default:
+		diff := old.StateFile.Diff(sf.StateFile)
+		if !diff.IsEmpty() || old.StateFile.Dttm != sf.StateFile.Dttm {
+			r.publish(Event{Type: EventStateFileUpdated, EASIN: sf.EASIN, Old: &old, New: &sf, Diff: diff})
+		}
+	}
+	return true
+}
+
+// Delete removes the SavedFile for the EASIN and sends subscribers an EventNodeRemoved, or returns ErrNotFound.
+func (r *Registry) Delete(easin string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, err := r.store.Get(easin)
+	if err != nil {
+		return err
+	}
+	if err := r.store.Delete(easin); err != nil {
+		return err
+	}
+	r.publish(Event{Type: EventNodeRemoved, EASIN: easin, Old: &old})
+	return nil
+}
+
+// Get returns the SavedFile for the EASIN, or ErrNotFound.
+func (r *Registry) Get(easin string) (SavedFile, error) {
+	return r.store.Get(easin)
+}
+
+// List returns the SavedFiles matching all the given FilePredicates, sorted by EASIN.
+func (r *Registry) List(filters ...FilePredicate) (SavedState, error) {
+	return r.store.List(filters...)
+}
+
+// Len returns the number of SavedFiles in the Registry.
+func (r *Registry) Len() int {
+	return r.store.Len()
+}
+
+// Snapshot returns a copy of all SavedFiles in the Registry, sorted by EASIN.
+func (r *Registry) Snapshot() (SavedState, error) {
+	return r.store.Snapshot()
+}
+
+// Subscribe returns a Subscription receiving the Events matching the given EventFilter.
+// Events are queued per Subscription, so a slow subscriber never blocks the Registry.
+func (r *Registry) Subscribe(filter EventFilter) *Subscription {
+	s := &Subscription{
+		registry: r,
+		filter:   filter,
+		notify:   make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		events:   make(chan Event),
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		s.stop()
+		close(s.events)
+		return s
+	}
+	r.subs[s] = true
+	go s.run()
+	return s
+}
+
+// Close closes all Subscriptions. Changes made after Close are not published.
+func (r *Registry) Close() {
+	r.mu.Lock()
+	subs := r.subs
+	r.subs = make(map[*Subscription]bool)
+	r.closed = true
+	r.mu.Unlock()
+	for s := range subs {
+		s.stop()
+	}
+}
+
+// publish queues a copy of the Event for each matching Subscription, r.mu must be held.
+func (r *Registry) publish(e Event) {
+	for s := range r.subs {
+		if s.filter.match(&e) {
+			s.enqueue(copyEvent(e))
+		}
+	}
+}
+
+// copyEvent returns a copy of the Event which shares no SavedFiles or Data with the original,
+// so subscribers can never affect each other or the caller.
+func copyEvent(e Event) Event {
+	if e.Old != nil {
+		old := copySavedFile(*e.Old)
+		e.Old = &old
+	}
+	if e.New != nil {
+		sf := copySavedFile(*e.New)
+		e.New = &sf
+	}
+	e.Diff = Diff{
+		Added:   copyChanges(e.Diff.Added),
+		Removed: copyChanges(e.Diff.Removed),
+		Changed: copyChanges(e.Diff.Changed),
+	}
+	return e
+}
+
+func copyChanges(changes []Change) []Change {
+	if changes == nil {
+		return nil
+	}
+	cp := make([]Change, len(changes))
+	for i, c := range changes {
+		if c.Old != nil {
+			d := copyData(*c.Old)
+			c.Old = &d
+		}
+		if c.New != nil {
+			d := copyData(*c.New)
+			c.New = &d
+		}
+		c.Fields = append([]string(nil), c.Fields...)
+		cp[i] = c
+	}
+	return cp
+}
+
+// Subscription receives Events from a Registry in the order changes were made.
+type Subscription struct {
+	registry *Registry
+	filter   EventFilter
+	mu       sync.Mutex
+	queue    []Event
+	notify   chan struct{}
+	done     chan struct{}
+	once     sync.Once
+	events   chan Event
+}
+
+// Events returns the channel Events are delivered on. It is closed once the Subscription is closed.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close stops the Subscription and closes its Events channel.
+func (s *Subscription) Close() {
+	r := s.registry
+	r.mu.Lock()
+	delete(r.subs, s)
+	r.mu.Unlock()
+	s.stop()
+}
+
+func (s *Subscription) stop() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+func (s *Subscription) enqueue(e Event) {
+	s.mu.Lock()
+	s.queue = append(s.queue, e)
+	s.mu.Unlock()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Subscription) run() {
+	defer close(s.events)
+	for {
+		s.mu.Lock()
+		queue := s.queue
+		s.queue = nil
+		s.mu.Unlock()
+		for _, e := range queue {
+			select {
+			case s.events <- e:
+			case <-s.done:
+				return
+			}
+		}
+		select {
+		case <-s.notify:
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package appconfig
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	file := func(env, node, port string, dttm float64) SavedFile {
+		return testFile(env, node, dttm, Data{Pkg: `packapi-sit20191024.103-0`, Key: `ports__ENVOY_HTTP_PORT`, Value: port})
+	}
+	reg := NewRegistry()
+	defer reg.Close()
+	all := reg.Subscribe(EventFilter{})
+	other := reg.Subscribe(EventFilter{})
+	prd := reg.Subscribe(EventFilter{ENV: `prd`})
+	keys := reg.Subscribe(EventFilter{Keys: []string{`timezone`}})
+	reg.Put(file(`srv`, `a`, `8000`, 1))
+	reg.Put(file(`prd`, `a`, `8000`, 1))
+	reg.Put(file(`srv`, `a`, `8001`, 2))
+	if reg.Upsert(file(`srv`, `a`, `8002`, 1)) {
+		t.Fatalf("expected upsert to keep the newer saved file")
+	}
+	reg.Delete(`prd:wm:app:packapi:a`)
+	next := func(s *Subscription) Event {
+		select {
+		case e := <-s.Events():
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event")
+		}
+		return Event{}
+	}
+	expected := []EventType{EventNodeAdded, EventNodeAdded, EventStateFileUpdated, EventNodeRemoved}
+	for i, et := range expected {
+		e := next(all)
+		if e.Type != et {
+			t.Fatalf("unexpected event %v, expected %v, got %v", i, et, e.Type)
+		}
+		if e.Type == EventStateFileUpdated && (len(e.Diff.Changed) != 1 || e.New.Collection()[0].Value != `8001`) {
+			t.Fatalf("unexpected update event: %+v", e)
+		}
+		if e.New != nil {
+			e.New.Collection()[0].Value = `changed`
+		}
+	}
+	if e := next(other); e.New.Collection()[0].Value != `8000` {
+		t.Fatalf("expected each subscriber to receive its own copy, got %+v", e.New.Collection())
+	}
+	if e := next(prd); e.Type != EventNodeAdded || e.New.ENV != `prd` {
+		t.Fatalf("unexpected prd event: %+v", e)
+	}
+	if e := next(prd); e.Type != EventNodeRemoved || e.Old.ENV != `prd` {
+		t.Fatalf("unexpected prd event: %+v", e)
+	}
+	snap, err := reg.Snapshot()
+	if err != nil || len(snap) != 1 || snap[0].StateFile.Dttm != 2 {
+		t.Fatalf("unexpected snapshot: %+v, %v", snap, err)
+	}
+	reg.Put(file(`srv`, `a`, `8002`, 1))
+	if sf, err := reg.Get(`srv:wm:app:packapi:a`); err != nil || sf.StateFile.Dttm != 1 {
+		t.Fatalf("expected put to replace the newer saved file, got %+v, %v", sf, err)
+	}
+	if err := reg.Delete(`prd:wm:app:packapi:a`); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound deleting twice, got %v", err)
+	}
+	keys.Close()
+	if _, ok := <-keys.Events(); ok {
+		t.Fatalf("expected no events for unmatched key filter")
+	}
+}
@@ -3,7 +3,7 @@ package appconfig
 import (
 	"crypto/sha1"
 	"fmt"
-	"sync"
+	"regexp"
 )
 
 // SavedState constains saved StateFiles.
@@ -18,32 +18,12 @@ func (s SavedState) Collection() Collection {
 	return data
 }
 
-// GetAll returns everything.
+// GetAll returns the unique envs, asis, easis, easins and nodes found in the SavedState, each sorted.
+//
+// Deprecated: Use Aggregate, which computes any set of dimensions in a single pass.
 func (s SavedState) GetAll() (envs, asis, easis, easins, nodes []string) {
-	var wg sync.WaitGroup
-	wg.Add(5)
-	go func(wg *sync.WaitGroup) {
-		defer wg.Done()
-		envs = s.ENVs()
-	}(&wg)
-	go func(wg *sync.WaitGroup) {
-		defer wg.Done()
-		asis = s.ASIs()
-	}(&wg)
-	go func(wg *sync.WaitGroup) {
-		defer wg.Done()
-		easis = s.EASIs()
-	}(&wg)
-	go func(wg *sync.WaitGroup) {
-		defer wg.Done()
-		easins = s.EASINs()
-	}(&wg)
-	go func(wg *sync.WaitGroup) {
-		defer wg.Done()
-		nodes = s.Nodes()
-	}(&wg)
-	wg.Wait()
-	return
+	agg := s.Aggregate(DimENV, DimASI, DimEASI, DimEASIN, DimNode)
+	return agg.Values(DimENV), agg.Values(DimASI), agg.Values(DimEASI), agg.Values(DimEASIN), agg.Values(DimNode)
 }
 
 // ENVs returns all the ENVs found in the SavedState.
@@ -106,14 +86,77 @@ func (s SavedState) Nodes() (nodes []string) {
 	return
 }
 
+// FromENV returns a sub SavedState containing only the SavedFiles with the given env.
+func (s SavedState) FromENV(env string) SavedState {
+	return s.Filter(ENVIs(env))
+}
+
+// FromENVRegexp returns a sub SavedState containing only the SavedFiles whose env matches the given regexp.
+func (s SavedState) FromENVRegexp(regex *regexp.Regexp) SavedState {
+	return s.Filter(ENVMatches(regex))
+}
+
+// FromASI returns a sub SavedState containing only the SavedFiles with the given asi.
+func (s SavedState) FromASI(asi string) SavedState {
+	return s.Filter(ASIIs(asi))
+}
+
+// FromASIRegexp returns a sub SavedState containing only the SavedFiles whose asi matches the given regexp.
+func (s SavedState) FromASIRegexp(regex *regexp.Regexp) SavedState {
+	return s.Filter(ASIMatches(regex))
+}
+
+// FromEASI returns a sub SavedState containing only the SavedFiles with the given easi.
+func (s SavedState) FromEASI(easi string) SavedState {
+	return s.Filter(EASIIs(easi))
+}
+
+// FromEASIRegexp returns a sub SavedState containing only the SavedFiles whose easi matches the given regexp.
+func (s SavedState) FromEASIRegexp(regex *regexp.Regexp) SavedState {
+	return s.Filter(EASIMatches(regex))
+}
+
+// FromNode returns a sub SavedState containing only the SavedFiles with the given node.
+func (s SavedState) FromNode(node string) SavedState {
+	return s.Filter(NodeIs(node))
+}
+
+// FromNodeRegexp returns a sub SavedState containing only the SavedFiles whose node matches the given regexp.
+func (s SavedState) FromNodeRegexp(regex *regexp.Regexp) SavedState {
+	return s.Filter(NodeMatches(regex))
+}
+
+// FromEASIN returns a sub SavedState containing only the SavedFiles with the given easin.
+func (s SavedState) FromEASIN(easin string) SavedState {
+	return s.Filter(EASINIs(easin))
+}
+
+// FromEASINRegexp returns a sub SavedState containing only the SavedFiles whose easin matches the given regexp.
+func (s SavedState) FromEASINRegexp(regex *regexp.Regexp) SavedState {
+	return s.Filter(EASINMatches(regex))
+}
+
+// FromData returns a sub SavedState containing only the SavedFiles whose Collection has data matching the given Predicate.
+func (s SavedState) FromData(p Predicate) SavedState {
+	return s.Filter(AnyData(p))
+}
+
+// FromKeyValue returns a sub SavedState containing only the SavedFiles whose Collection has the given key set to the given value.
+func (s SavedState) FromKeyValue(key, value string) SavedState {
+	return s.FromData(And(KeyIs(key), ValueIs(value)))
+}
+
 // SavedFile is a StateFile in a saved state prepared for retrieval.
 type SavedFile struct {
-	ENV       string    `json:"env"`
-	ASI       string    `json:"asi"`
-	EASI      string    `json:"easi"`
-	Node      string    `json:"node"`
-	EASIN     string    `json:"easin"`
-	StateFile StateFile `json:"statefile"`
+	ENV        string    `json:"env"`
+	ASI        string    `json:"asi"`
+	EASI       string    `json:"easi"`
+	Node       string    `json:"node"`
+	EASIN      string    `json:"easin"`
+	Datacenter string    `json:"datacenter"`
+	Workgroup  string    `json:"workgroup"`
+	Source     string    `json:"source"`
+	StateFile  StateFile `json:"statefile"`
 }
 
 // Collection returns the underlying Collection from the SavedFile.
@@ -146,6 +189,16 @@ func (s *SavedFile) HasEASIN(easin string) bool {
 	return s.EASIN == easin
 }
 
+// HasDatacenter returns true if the entered string matches the datacenter, false otherwise.
+func (s *SavedFile) HasDatacenter(dc string) bool {
+	return s.Datacenter == dc
+}
+
+// HasWorkgroup returns true if the entered string matches the workgroup, false otherwise.
+func (s *SavedFile) HasWorkgroup(wg string) bool {
+	return s.Workgroup == wg
+}
+
 // SHA returns the Sha1 string using the EASIN.
 func (s *SavedFile) SHA() string {
 	b := []byte(s.EASI + `:` + s.Node)
@@ -0,0 +1,25 @@
+package appconfig
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestSavedStateFilters(t *testing.T) {
+	port := func(v string) Data { return Data{Key: `ports__ENVOY_HTTP_PORT`, Value: v} }
+	SS := SavedState{testFile(`srv`, `a`, 0, port(`8000`)), testFile(`srv`, `b`, 0, port(`8001`)), testFile(`prd`, `a`, 0, port(`8000`))}
+	switch {
+	case len(SS.FromENV(`srv`)) != 2 || len(SS.FromASI(`wm:app:packapi`)) != 3:
+		t.Fatalf("incorrect env or asi filter results")
+	case len(SS.FromEASI(`prd:wm:app:packapi`)) != 1 || len(SS.FromNode(`a`)) != 2:
+		t.Fatalf("incorrect easi or node filter results")
+	case len(SS.FromEASIN(`srv:wm:app:packapi:b`)) != 1 || len(SS.FromEASINRegexp(regexp.MustCompile(`:a$`))) != 2:
+		t.Fatalf("incorrect easin filter results")
+	case len(SS.FromENVRegexp(regexp.MustCompile(`^(srv|prd)$`))) != 3 || len(SS.FromNodeRegexp(regexp.MustCompile(`^b`))) != 1:
+		t.Fatalf("incorrect regexp filter results")
+	}
+	matched := SS.FromKeyValue(`ports__ENVOY_HTTP_PORT`, `8000`)
+	if len(matched) != 2 || len(matched.FromENV(`srv`)) != 1 {
+		t.Fatalf("unexpected join results: %+v", matched)
+	}
+}
@@ -1,9 +1,11 @@
 package appconfig
 
 import (
+	"bytes"
 	"crypto/sha1"
 	"encoding/json"
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -20,35 +22,84 @@ func (s *StateFile) Time() time.Time {
 
 // KafkaMSG is how the statefile arrives in Kafka.
 type KafkaMSG struct {
-	ENV     string `json:"env"`
-	ASI     string `json:"asi"`
-	EASI    string `json:"easi"`
-	Node    string `json:"node"`
-	Message string `json:"message"`
+	Timestamp    time.Time     `json:"@timestamp"`
+	Metadata     KafkaMetadata `json:"@metadata"`
+	ENV          string        `json:"env"`
+	ASI          string        `json:"asi"`
+	EASI         string        `json:"easi"`
+	Node         string        `json:"node"`
+	Datacenter   string        `json:"datacenter"`
+	Workgroup    string        `json:"workgroup"`
+	Host         KafkaHost     `json:"host"`
+	Log          string        `json:"log"`
+	Source       string        `json:"source"`
+	Offset       int64         `json:"offset"`
+	Input        KafkaInput    `json:"input"`
+	Prospector   KafkaInput    `json:"prospector"`
+	Pipeline     KafkaPipeline `json:"pipeline"`
+	Beat         KafkaBeat     `json:"beat"`
+	StreamSource string        `json:"streamSource"`
+	Message      string        `json:"message"`
+}
+
+// KafkaMetadata is the @metadata section added by the shipping beat.
+type KafkaMetadata struct {
+	Beat    string `json:"beat"`
+	Type    string `json:"type"`
+	Version string `json:"version"`
+	Topic   string `json:"topic"`
+}
+
+// KafkaHost is the host section of a KafkaMSG.
+type KafkaHost struct {
+	Name string `json:"name"`
+}
+
+// KafkaInput is the input or prospector section of a KafkaMSG.
+type KafkaInput struct {
+	Type string `json:"type"`
+}
+
+// KafkaPipeline is the pipeline section of a KafkaMSG.
+type KafkaPipeline struct {
+	Topic  string `json:"topic"`
+	Source string `json:"source"`
+}
+
+// KafkaBeat is the beat section of a KafkaMSG.
+type KafkaBeat struct {
+	Name     string `json:"name"`
+	Hostname string `json:"hostname"`
+	Version  string `json:"version"`
 }
 
 // StateFile returns the StateFile from a KafkaMSG.
 func (k *KafkaMSG) StateFile() (stateFile StateFile, err error) {
-	err = json.Unmarshal([]byte(k.Message), &stateFile)
-	stateFile.AssignADs(stateFile.findDefaultADs())
-	return
+	return parseStateFile([]byte(k.Message))
+}
+
+// StrictStateFile returns the StateFile from a KafkaMSG, validating every entry.
+// All problems found are returned together as ParseErrors.
+func (k *KafkaMSG) StrictStateFile() (stateFile StateFile, err error) {
+	return ParseStateFileStrict([]byte(k.Message))
 }
 
 // SavedFile returns a SavedFile from a KafkaMSG.
 func (k *KafkaMSG) SavedFile() (savedFile SavedFile, err error) {
-	var stateFile StateFile
-	err = json.Unmarshal([]byte(k.Message), &stateFile)
+	stateFile, err := k.StateFile()
 	if err != nil {
 		return
 	}
-	stateFile.AssignADs(stateFile.findDefaultADs())
 	savedFile = SavedFile{
-		ENV:       k.ENV,
-		ASI:       k.ASI,
-		EASI:      k.EASI,
-		EASIN:     k.EASIN(),
-		Node:      k.Node,
-		StateFile: stateFile,
+		ENV:        k.ENV,
+		ASI:        k.ASI,
+		EASI:       k.EASI,
+		EASIN:      k.EASIN(),
+		Node:       k.Node,
+		Datacenter: k.Datacenter,
+		Workgroup:  k.Workgroup,
+		Source:     k.Source,
+		StateFile:  stateFile,
 	}
 	return
 }
@@ -64,6 +115,62 @@ func (k *KafkaMSG) SHA() string {
 	return fmt.Sprintf("%x", sha1.Sum(b))
 }
 
+func parseStateFile(b []byte) (stateFile StateFile, err error) {
+	err = json.Unmarshal(b, &stateFile)
+	if err != nil {
+		return
+	}
+	stateFile.AssignADs(stateFile.findDefaultADs())
+	return
+}
+
+// ParseStateFileStrict parses a StateFile, validating every entry.
+// Each entry is decoded on its own, so an entry with malformed fields is reported with its index and left out of the Collection
+// while the remaining entries are still validated.
+// All problems found are returned together as ParseErrors, along with whatever could be parsed.
+func ParseStateFileStrict(b []byte) (stateFile StateFile, err error) {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return stateFile, ParseErrors{{Kind: ErrKindEmptyMessage, Index: -1}}
+	}
+	var raw struct {
+		Dttm json.RawMessage   `json:"dttm"`
+		Data []json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return stateFile, ParseErrors{{Kind: ErrKindBadJSON, Index: -1, Detail: err.Error()}}
+	}
+	var errs ParseErrors
+	switch {
+	case len(raw.Dttm) > 0 && json.Unmarshal(raw.Dttm, &stateFile.Dttm) != nil:
+		errs = append(errs, &ParseError{Kind: ErrKindInvalidDttm, Index: -1, Detail: string(raw.Dttm)})
+	case stateFile.Dttm <= 0 || math.IsNaN(stateFile.Dttm) || math.IsInf(stateFile.Dttm, 0):
+		errs = append(errs, &ParseError{Kind: ErrKindInvalidDttm, Index: -1, Detail: fmt.Sprintf("%v", stateFile.Dttm)})
+	}
+	for i, entry := range raw.Data {
+		var d Data
+		if err := json.Unmarshal(entry, &d); err != nil {
+			// d holds whatever fields decoded before and after the bad one, the key if it was readable.
+			errs = append(errs, &ParseError{Kind: ErrKindBadJSON, Index: i, Key: d.Key, Detail: err.Error()})
+			continue
+		}
+		if d.DataType() == TypeInvalid {
+			errs = append(errs, &ParseError{Kind: ErrKindUnknownType, Index: i, Key: d.Key, Detail: fmt.Sprintf("%q", d.T)})
+		}
+		if d.Key == "" {
+			errs = append(errs, &ParseError{Kind: ErrKindMissingKey, Index: i, Key: d.Key})
+		}
+		if d.Pkg == "" {
+			errs = append(errs, &ParseError{Kind: ErrKindMissingPkg, Index: i, Key: d.Key})
+		}
+		stateFile.Collection = append(stateFile.Collection, d)
+	}
+	stateFile.AssignADs(stateFile.findDefaultADs())
+	if len(errs) > 0 {
+		return stateFile, errs
+	}
+	return stateFile, nil
+}
+
 func timeFromFloat64(ts float64) time.Time {
 	secs := int64(ts)
 	nsecs := int64((ts - float64(secs)) * 1e9)
@@ -0,0 +1,84 @@
+package appconfig
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKafkaMSGEnvelope(t *testing.T) {
+	kMsg := testKafkaMSG(t)
+	ts := time.Date(2019, 10, 24, 21, 3, 12, 9e6, time.UTC)
+	switch {
+	case !kMsg.Timestamp.Equal(ts):
+		t.Fatalf("incorrect timestamp, expected %v, got %v", ts, kMsg.Timestamp)
+	case kMsg.Host.Name != `srv24w0m15.example.com`:
+		t.Fatalf("incorrect host name, got %q", kMsg.Host.Name)
+	case kMsg.Datacenter != `m15` || kMsg.Workgroup != `w05`:
+		t.Fatalf("incorrect datacenter or workgroup, got %q %q", kMsg.Datacenter, kMsg.Workgroup)
+	case kMsg.Offset != 92453:
+		t.Fatalf("incorrect offset, expected %v, got %v", 92453, kMsg.Offset)
+	case kMsg.Pipeline.Topic != `srv-appconfig-event-json`:
+		t.Fatalf("incorrect pipeline topic, got %q", kMsg.Pipeline.Topic)
+	case kMsg.Beat.Version != `6.7.2`:
+		t.Fatalf("incorrect beat version, got %q", kMsg.Beat.Version)
+	}
+	sf, err := kMsg.SavedFile()
+	if err != nil {
+		t.Fatalf("error converting kafka message into savedfile: %v", err)
+	}
+	switch {
+	case !sf.HasDatacenter(`m15`) || !sf.HasWorkgroup(`w05`):
+		t.Fatalf("savedfile did not carry datacenter and workgroup")
+	case sf.Source != `/example/srv-wm-app-packapi/logs/appconfig-install.state.json`:
+		t.Fatalf("incorrect source, got %q", sf.Source)
+	}
+}
+
+func TestParseStateFileStrict(t *testing.T) {
+	_, err := ParseStateFileStrict([]byte(` `))
+	if errs, ok := err.(ParseErrors); !ok || !errs.HasKind(ErrKindEmptyMessage) {
+		t.Fatalf("expected empty message error, got %v", err)
+	}
+	_, err = ParseStateFileStrict([]byte(`{"data": [`))
+	if errs, ok := err.(ParseErrors); !ok || !errs.HasKind(ErrKindBadJSON) {
+		t.Fatalf("expected bad json error, got %v", err)
+	}
+	raw := `{"data": [{"k": "node", "pkg": "packapi-sit20191024.103-0", "type": "simple", "v": "srv24w0m15"}, {"k": "bogus", "pkg": "", "type": "other", "v": "1"}, {"k": "", "pkg": "packapi-sit20191024.103-0", "type": "parameter", "v": "2"}], "dttm": 0}`
+	_, err = ParseStateFileStrict([]byte(raw))
+	checkParseErrors(t, err, []ParseError{
+		{Kind: ErrKindInvalidDttm, Index: -1},
+		{Kind: ErrKindUnknownType, Index: 1, Key: `bogus`},
+		{Kind: ErrKindMissingPkg, Index: 1, Key: `bogus`},
+		{Kind: ErrKindMissingKey, Index: 2},
+	})
+	raw = `{"data": [{"k": "port", "pkg": "packapi-sit20191024.103-0", "type": "parameter", "v": 17}, {"k": "", "pkg": "packapi-sit20191024.103-0", "type": "parameter", "v": "2"}, {"k": "node", "pkg": "packapi-sit20191024.103-0", "type": "simple", "v": "srv24w0m15"}], "dttm": "now"}`
+	stateFile, err := ParseStateFileStrict([]byte(raw))
+	checkParseErrors(t, err, []ParseError{
+		{Kind: ErrKindInvalidDttm, Index: -1},
+		{Kind: ErrKindBadJSON, Index: 0, Key: `port`},
+		{Kind: ErrKindMissingKey, Index: 1},
+	})
+	if len(stateFile.Collection) != 2 {
+		t.Fatalf("incorrect number of parsed entries, expected %v, got %v", 2, len(stateFile.Collection))
+	}
+	kMsg := testKafkaMSG(t)
+	if _, err := kMsg.StrictStateFile(); err != nil {
+		t.Fatalf("unexpected error parsing valid statefile: %v", err)
+	}
+}
+
+// checkParseErrors compares the Kind, Index and Key of each ParseError found in err.
+func checkParseErrors(t *testing.T, err error, expected []ParseError) {
+	errs, ok := err.(ParseErrors)
+	if !ok {
+		t.Fatalf("expected ParseErrors, got %T", err)
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("incorrect number of errors, expected %v, got %v: %v", len(expected), len(errs), errs)
+	}
+	for i, e := range expected {
+		if errs[i].Kind != e.Kind || errs[i].Index != e.Index || errs[i].Key != e.Key {
+			t.Fatalf("unexpected error at %v, expected %v, got %v", i, &e, errs[i])
+		}
+	}
+}
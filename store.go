@@ -0,0 +1,155 @@
+package appconfig
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrNotFound is returned by a Store when no SavedFile exists for an EASIN.
+var ErrNotFound = errors.New("not found")
+
+// Sink receives SavedFiles as they are decoded.
+type Sink interface {
+	Put(SavedFile) error
+}
+
+// Store persists SavedFiles keyed by EASIN.
+// Put always replaces the SavedFile stored for the EASIN, even one with a newer StateFile.Dttm.
+// Keeping only the newest SavedFile is left to the caller, StateStore and Registry provide Upsert for it.
+// List and Snapshot return SavedFiles sorted by EASIN.
+type Store interface {
+	Sink
+	// Get returns the SavedFile for the EASIN, or ErrNotFound.
+	Get(easin string) (SavedFile, error)
+	// List returns the SavedFiles matching all the given FilePredicates, sorted by EASIN.
+	List(filters ...FilePredicate) (SavedState, error)
+	// Delete removes the SavedFile for the EASIN, or returns ErrNotFound.
+	Delete(easin string) error
+	// Snapshot returns every stored SavedFile, sorted by EASIN.
+	Snapshot() (SavedState, error)
+}
+
+var _ Store = (*StateStore)(nil)
+
+// StateStore is an in-memory Store backed by a SavedState and safe for concurrent use.
+type StateStore struct {
+	mu    sync.RWMutex
+	state SavedState
+	index map[string]int
+}
+
+// NewStateStore returns a new StateStore seeded with the given SavedFiles.
+func NewStateStore(savedFiles ...SavedFile) *StateStore {
+	s := &StateStore{
+		index: make(map[string]int),
+	}
+	for _, sf := range savedFiles {
+		s.Put(sf)
+	}
+	return s
+}
+
+// Put stores a copy of the SavedFile, replacing any SavedFile with the same EASIN.
+func (s *StateStore) Put(savedFile SavedFile) error {
+	savedFile = copySavedFile(savedFile)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i, ok := s.index[savedFile.EASIN]; ok {
+		s.state[i] = savedFile
+		return nil
+	}
+	s.index[savedFile.EASIN] = len(s.state)
+	s.state = append(s.state, savedFile)
+	return nil
+}
+
+// Get returns the SavedFile for the EASIN, or ErrNotFound.
+func (s *StateStore) Get(easin string) (SavedFile, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, ok := s.index[easin]
+	if !ok {
+		return SavedFile{}, ErrNotFound
+	}
+	return copySavedFile(s.state[i]), nil
+}
+
+// List returns the SavedFiles matching all the given FilePredicates, sorted by EASIN.
+func (s *StateStore) List(filters ...FilePredicate) (SavedState, error) {
+	s.mu.RLock()
+	state := copySavedState(s.state.Filter(FileAnd(filters...)))
+	s.mu.RUnlock()
+	sortByEASIN(state)
+	return state, nil
+}
+
+// Delete removes the SavedFile for the EASIN, or returns ErrNotFound.
+func (s *StateStore) Delete(easin string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, ok := s.index[easin]
+	if !ok {
+		return ErrNotFound
+	}
+	s.state = append(s.state[:i], s.state[i+1:]...)
+	delete(s.index, easin)
+	for ; i < len(s.state); i++ {
+		s.index[s.state[i].EASIN] = i
+	}
+	return nil
+}
+
+// Snapshot returns a copy of every stored SavedFile, sorted by EASIN.
+func (s *StateStore) Snapshot() (SavedState, error) {
+	state := s.SavedState()
+	sortByEASIN(state)
+	return state, nil
+}
+
+// SavedState returns a copy of the stored SavedState in the order SavedFiles were first stored,
+// changes to it do not affect the StateStore.
+func (s *StateStore) SavedState() SavedState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return copySavedState(s.state)
+}
+
+// Len returns the number of stored SavedFiles.
+func (s *StateStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.state)
+}
+
+func sortByEASIN(state SavedState) {
+	sort.Slice(state, func(i, j int) bool { return state[i].EASIN < state[j].EASIN })
+}
+
+// copySavedState returns a deep copy of the SavedState.
+func copySavedState(state SavedState) SavedState {
+	if state == nil {
+		return nil
+	}
+	cp := make(SavedState, len(state))
+	for i := range state {
+		cp[i] = copySavedFile(state[i])
+	}
+	return cp
+}
+
+// copySavedFile returns a copy of the SavedFile which shares no Data with the original.
+func copySavedFile(sf SavedFile) SavedFile {
+	if sf.StateFile.Collection == nil {
+		return sf
+	}
+	c := make(Collection, len(sf.StateFile.Collection))
+	copy(c, sf.StateFile.Collection)
+	for i := range c {
+		if c[i].Tpls != nil {
+			c[i].Tpls = append([]string(nil), c[i].Tpls...)
+		}
+	}
+	sf.StateFile.Collection = c
+	return sf
+}
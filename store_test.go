@@ -0,0 +1,41 @@
+package appconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/jbvmio/appconfig"
+	"github.com/jbvmio/appconfig/storetest"
+)
+
+func TestStateStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) appconfig.Store {
+		return appconfig.NewStateStore()
+	})
+}
+
+func TestRegistryStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) appconfig.Store {
+		return appconfig.NewRegistry()
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	root, err := ioutil.TempDir("", "appconfig-store")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+	storetest.Run(t, func(t *testing.T) appconfig.Store {
+		dir, err := ioutil.TempDir(root, "store")
+		if err != nil {
+			t.Fatalf("error creating temp dir: %v", err)
+		}
+		s, err := appconfig.NewFileStore(dir)
+		if err != nil {
+			t.Fatalf("error creating file store: %v", err)
+		}
+		return s
+	})
+}
@@ -0,0 +1,157 @@
+// Package storetest provides a conformance test suite for appconfig.Store implementations.
+package storetest
+
+import (
+	"testing"
+
+	"github.com/jbvmio/appconfig"
+)
+
+// Run runs the conformance suite against Stores returned by newStore.
+// Each subtest calls newStore once and expects an empty Store.
+func Run(t *testing.T, newStore func(t *testing.T) appconfig.Store) {
+	t.Run(`PutGet`, func(t *testing.T) { testPutGet(t, newStore(t)) })
+	t.Run(`Replace`, func(t *testing.T) { testReplace(t, newStore(t)) })
+	t.Run(`ReplaceOlder`, func(t *testing.T) { testReplaceOlder(t, newStore(t)) })
+	t.Run(`List`, func(t *testing.T) { testList(t, newStore(t)) })
+	t.Run(`Delete`, func(t *testing.T) { testDelete(t, newStore(t)) })
+	t.Run(`Snapshot`, func(t *testing.T) { testSnapshot(t, newStore(t)) })
+}
+
+func savedFile(env, node, port string, dttm float64) appconfig.SavedFile {
+	easi := env + `:wm:app:packapi`
+	return appconfig.SavedFile{
+		ENV:   env,
+		ASI:   `wm:app:packapi`,
+		EASI:  easi,
+		Node:  node,
+		EASIN: easi + `:` + node,
+		StateFile: appconfig.StateFile{
+			Dttm: dttm,
+			Collection: appconfig.Collection{
+				{T: `parameter`, Pkg: `packapi-sit20191024.103-0`, Key: `ports__ENVOY_HTTP_PORT`, Src: `default`, Value: port, Tpls: []string{`config/envoy.yaml`}},
+			},
+		},
+	}
+}
+
+func put(t *testing.T, s appconfig.Store, files ...appconfig.SavedFile) {
+	for _, sf := range files {
+		if err := s.Put(sf); err != nil {
+			t.Fatalf("error putting %v: %v", sf.EASIN, err)
+		}
+	}
+}
+
+func testPutGet(t *testing.T, s appconfig.Store) {
+	sf := savedFile(`srv`, `a`, `8000`, 1571950979.575358)
+	put(t, s, sf)
+	got, err := s.Get(sf.EASIN)
+	switch {
+	case err != nil:
+		t.Fatalf("error getting %v: %v", sf.EASIN, err)
+	case got.EASIN != sf.EASIN || got.ENV != sf.ENV || got.Node != sf.Node || got.StateFile.Dttm != sf.StateFile.Dttm:
+		t.Fatalf("unexpected saved file: %+v", got)
+	case len(got.Collection()) != 1 || got.Collection()[0].Value != `8000` || len(got.Collection()[0].Tpls) != 1:
+		t.Fatalf("unexpected collection: %+v", got.Collection())
+	}
+	if _, err := s.Get(`missing`); err != appconfig.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func testReplace(t *testing.T, s appconfig.Store) {
+	put(t, s, savedFile(`srv`, `a`, `8000`, 1), savedFile(`srv`, `a`, `8001`, 2))
+	state, err := s.Snapshot()
+	switch {
+	case err != nil:
+		t.Fatalf("error taking snapshot: %v", err)
+	case len(state) != 1 || state[0].Collection()[0].Value != `8001`:
+		t.Fatalf("expected put to replace the saved file, got %+v", state)
+	}
+}
+
+func testReplaceOlder(t *testing.T, s appconfig.Store) {
+	put(t, s, savedFile(`srv`, `a`, `8001`, 2), savedFile(`srv`, `a`, `8000`, 1))
+	sf, err := s.Get(`srv:wm:app:packapi:a`)
+	switch {
+	case err != nil:
+		t.Fatalf("error getting saved file: %v", err)
+	case sf.StateFile.Dttm != 1 || sf.Collection()[0].Value != `8000`:
+		t.Fatalf("expected put to replace a newer saved file, got %+v", sf)
+	}
+}
+
+// checkOrder fails unless the SavedState is sorted by EASIN.
+func checkOrder(t *testing.T, state appconfig.SavedState) {
+	for i := 1; i < len(state); i++ {
+		if state[i-1].EASIN >= state[i].EASIN {
+			t.Fatalf("saved files not sorted by EASIN: %v, %v", state[i-1].EASIN, state[i].EASIN)
+		}
+	}
+}
+
+func testList(t *testing.T, s appconfig.Store) {
+	put(t, s, savedFile(`srv`, `a`, `8000`, 1), savedFile(`srv`, `b`, `8001`, 1), savedFile(`prd`, `a`, `8000`, 1))
+	all, err := s.List()
+	if err != nil || len(all) != 3 {
+		t.Fatalf("expected 3 saved files, got %v, %v", len(all), err)
+	}
+	checkOrder(t, all)
+	srv, err := s.List(appconfig.ENVIs(`srv`))
+	if err != nil || len(srv) != 2 {
+		t.Fatalf("expected 2 srv saved files, got %v, %v", len(srv), err)
+	}
+	matched, err := s.List(appconfig.ENVIs(`srv`), appconfig.AnyData(appconfig.ValueIs(`8000`)))
+	if err != nil || len(matched) != 1 || matched[0].Node != `a` {
+		t.Fatalf("expected 1 matching saved file, got %+v, %v", matched, err)
+	}
+}
+
+func testDelete(t *testing.T, s appconfig.Store) {
+	a, b := savedFile(`srv`, `a`, `8000`, 1), savedFile(`srv`, `b`, `8000`, 1)
+	put(t, s, a, b)
+	if err := s.Delete(a.EASIN); err != nil {
+		t.Fatalf("error deleting %v: %v", a.EASIN, err)
+	}
+	if _, err := s.Get(a.EASIN); err != appconfig.ErrNotFound {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+	if _, err := s.Get(b.EASIN); err != nil {
+		t.Fatalf("error getting %v after deleting another: %v", b.EASIN, err)
+	}
+	if err := s.Delete(a.EASIN); err != appconfig.ErrNotFound {
+		t.Fatalf("expected ErrNotFound deleting twice, got %v", err)
+	}
+}
+
+func testSnapshot(t *testing.T, s appconfig.Store) {
+	put(t, s, savedFile(`srv`, `b`, `8000`, 1), savedFile(`srv`, `a`, `8000`, 1))
+	state, err := s.Snapshot()
+	if err != nil || len(state) != 2 {
+		t.Fatalf("expected 2 saved files, got %v, %v", len(state), err)
+	}
+	checkOrder(t, state)
+	put(t, s, savedFile(`srv`, `c`, `8000`, 1))
+	if len(state) != 2 {
+		t.Fatalf("snapshot changed after put")
+	}
+	state[0].Node = `changed`
+	if _, err := s.Get(`srv:wm:app:packapi:changed`); err != appconfig.ErrNotFound {
+		t.Fatalf("modifying a snapshot changed the store")
+	}
+	easin := state[1].EASIN
+	state[1].StateFile.Collection[0].Value = `changed`
+	state[1].StateFile.Collection[0].Tpls[0] = `changed`
+	sf, err := s.Get(easin)
+	switch {
+	case err != nil:
+		t.Fatalf("error getting %v: %v", easin, err)
+	case sf.StateFile.Collection[0].Value != `8000` || sf.StateFile.Collection[0].Tpls[0] != `config/envoy.yaml`:
+		t.Fatalf("modifying snapshot data changed the store: %+v", sf.StateFile.Collection[0])
+	}
+	sf.StateFile.Collection[0].Value = `changed`
+	if sf, _ = s.Get(easin); sf.StateFile.Collection[0].Value != `8000` {
+		t.Fatalf("modifying data returned by Get changed the store")
+	}
+}
@@ -0,0 +1,116 @@
+package appconfig
+
+import (
+	"regexp"
+	"sort"
+)
+
+// Tpls returns all the templates found in the Collection.
+func (c Collection) Tpls() (tpls []string) {
+	dupe := make(map[string]bool)
+	for _, d := range c {
+		for _, tpl := range d.Tpls {
+			if !dupe[tpl] {
+				dupe[tpl] = true
+				tpls = append(tpls, tpl)
+			}
+		}
+	}
+	return
+}
+
+// FromTpl returns a sub Collection containing only the data feeding the given template.
+func (c Collection) FromTpl(tpl string) Collection {
+	return c.Filter(TplIs(tpl))
+}
+
+// FromTplRegexp returns a sub Collection containing only the data feeding a template matching the given regexp.
+func (c Collection) FromTplRegexp(regex *regexp.Regexp) Collection {
+	return c.Filter(TplMatches(regex))
+}
+
+// HasTpl returns true if the data feeds the given template, false otherwise.
+func (d *Data) HasTpl(tpl string) bool {
+	return TplIs(tpl)(d)
+}
+
+// TplInventory maps each template to the data feeding it.
+type TplInventory map[string]Collection
+
+// TplInventory returns the TplInventory for the Collection.
+func (c Collection) TplInventory() TplInventory {
+	inv := make(TplInventory)
+	for _, d := range c {
+		dupe := make(map[string]bool, len(d.Tpls))
+		for _, tpl := range d.Tpls {
+			if !dupe[tpl] {
+				dupe[tpl] = true
+				inv[tpl] = append(inv[tpl], d)
+			}
+		}
+	}
+	return inv
+}
+
+// Tpls returns the sorted templates found in the TplInventory.
+func (t TplInventory) Tpls() []string {
+	tpls := make([]string, 0, len(t))
+	for tpl := range t {
+		tpls = append(tpls, tpl)
+	}
+	sort.Strings(tpls)
+	return tpls
+}
+
+// Keys returns all the keys feeding the given template.
+func (t TplInventory) Keys(tpl string) []string {
+	return t[tpl].Keys()
+}
+
+// Values returns all the values feeding the given template.
+func (t TplInventory) Values(tpl string) []string {
+	return t[tpl].Values()
+}
+
+// TplInput is a single input to a template.
+type TplInput struct {
+	Key       string `json:"k"`
+	Value     string `json:"v"`
+	Src       string `json:"src"`
+	Pkg       string `json:"pkg"`
+	AppDomain string `json:"appdomain"`
+}
+
+// TplReport lists the inputs going into a template.
+type TplReport struct {
+	Tpl    string     `json:"tpl"`
+	Inputs []TplInput `json:"inputs"`
+}
+
+// EffectiveInputs returns the report of inputs going into the given template, sorted by key.
+func (c Collection) EffectiveInputs(tpl string) TplReport {
+	report := TplReport{Tpl: tpl}
+	for _, d := range c.FromTpl(tpl) {
+		report.Inputs = append(report.Inputs, TplInput{
+			Key:       d.Key,
+			Value:     d.Value,
+			Src:       d.Src,
+			Pkg:       d.Pkg,
+			AppDomain: d.AppDomain,
+		})
+	}
+	sort.SliceStable(report.Inputs, func(i, j int) bool {
+		return report.Inputs[i].Key < report.Inputs[j].Key
+	})
+	return report
+}
+
+// TplReports returns the EffectiveInputs report for every template in the Collection, sorted by template.
+func (c Collection) TplReports() []TplReport {
+	inv := c.TplInventory()
+	reports := make([]TplReport, 0, len(inv))
+	for _, tpl := range inv.Tpls() {
+		reports = append(reports, inv[tpl].EffectiveInputs(tpl))
+	}
+	return reports
+}
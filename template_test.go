@@ -0,0 +1,21 @@
+package appconfig
+
+import "testing"
+
+func TestTplInventory(t *testing.T) {
+	stateFile := testSavedFile(t).StateFile
+	inv := stateFile.TplInventory()
+	switch {
+	case len(stateFile.Tpls()) != 7 || len(inv.Tpls()) != 7:
+		t.Fatalf("incorrect number of templates, got %v", stateFile.Tpls())
+	case len(inv.Keys(`config/advisor.conf`)) != 5:
+		t.Fatalf("incorrect advisor.conf keys, got %v", inv.Keys(`config/advisor.conf`))
+	}
+	report := stateFile.EffectiveInputs(`config/envoy.yaml`)
+	if len(report.Inputs) != 5 || report.Inputs[0].Key != `environment__e_ir` || report.Inputs[4].Key != `ports__ENVOY_HTTP_PORT` {
+		t.Fatalf("unexpected envoy.yaml report: %+v", report)
+	}
+	if n := len(stateFile.TplReports()); n != 7 {
+		t.Fatalf("incorrect number of reports, expected %v, got %v", 7, n)
+	}
+}
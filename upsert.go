@@ -0,0 +1,59 @@
+package appconfig
+
+// Upsert returns the SavedState with the SavedFile added, or replacing the SavedFile with the same EASIN.
+// An existing SavedFile with a newer StateFile.Dttm is kept, in which case false is returned.
+func (s SavedState) Upsert(savedFile SavedFile) (SavedState, bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i].EASIN == savedFile.EASIN {
+			if !supersedes(savedFile, s[i]) {
+				return s, false
+			}
+			s[i] = savedFile
+			return s, true
+		}
+	}
+	return append(s, savedFile), true
+}
+
+// Compact collapses the SavedState down to the SavedFile with the newest StateFile.Dttm per EASIN.
+// The order in which EASINs are first found is kept, the dropped SavedFiles are returned separately.
+func (s SavedState) Compact() (compacted, dropped SavedState) {
+	index := make(map[string]int)
+	for _, sf := range s {
+		i, ok := index[sf.EASIN]
+		switch {
+		case !ok:
+			index[sf.EASIN] = len(compacted)
+			compacted = append(compacted, sf)
+		case !supersedes(sf, compacted[i]):
+			dropped = append(dropped, sf)
+		default:
+			dropped = append(dropped, compacted[i])
+			compacted[i] = sf
+		}
+	}
+	return
+}
+
+// Upsert stores a copy of the SavedFile unless a SavedFile with the same EASIN and a newer StateFile.Dttm is already stored.
+// It returns true if the SavedFile was stored.
+func (s *StateStore) Upsert(savedFile SavedFile) bool {
+	savedFile = copySavedFile(savedFile)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i, ok := s.index[savedFile.EASIN]; ok {
+		if !supersedes(savedFile, s.state[i]) {
+			return false
+		}
+		s.state[i] = savedFile
+		return true
+	}
+	s.index[savedFile.EASIN] = len(s.state)
+	s.state = append(s.state, savedFile)
+	return true
+}
+
+// supersedes returns true if the SavedFile should replace the existing SavedFile for its EASIN, it is not older.
+func supersedes(savedFile, existing SavedFile) bool {
+	return savedFile.StateFile.Dttm >= existing.StateFile.Dttm
+}
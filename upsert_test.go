@@ -0,0 +1,26 @@
+package appconfig
+
+import "testing"
+
+func TestUpsertCompact(t *testing.T) {
+	var SS SavedState
+	var ok bool
+	SS, _ = SS.Upsert(testFile(`srv`, `a`, 200))
+	SS, _ = SS.Upsert(testFile(`srv`, `b`, 100))
+	if SS, ok = SS.Upsert(testFile(`srv`, `a`, 100)); ok {
+		t.Fatalf("expected older saved file to be rejected")
+	}
+	if SS, ok = SS.Upsert(testFile(`srv`, `a`, 300)); !ok {
+		t.Fatalf("expected newer saved file to be kept")
+	}
+	if len(SS) != 2 || SS[0].StateFile.Dttm != 300 {
+		t.Fatalf("unexpected saved state after upserts: %+v", SS)
+	}
+	compacted, dropped := SavedState{testFile(`srv`, `a`, 100), testFile(`srv`, `b`, 100), testFile(`srv`, `a`, 300), testFile(`srv`, `a`, 200)}.Compact()
+	switch {
+	case len(compacted) != 2 || compacted[0].Node != `a` || compacted[0].StateFile.Dttm != 300:
+		t.Fatalf("unexpected compacted state: %+v", compacted)
+	case len(dropped) != 2 || dropped[0].StateFile.Dttm != 100 || dropped[1].StateFile.Dttm != 200:
+		t.Fatalf("unexpected dropped files: %+v", dropped)
+	}
+}
@@ -0,0 +1,178 @@
+package appconfig
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetString returns the single value found for the given key.
+// A *ValueError is returned if the key is missing or has more than one unique value.
+func (c Collection) GetString(key string) (string, error) {
+	return single("", key, c.Get(key))
+}
+
+// GetInt returns the single value found for the given key as an int.
+func (c Collection) GetInt(key string) (int, error) {
+	return toInt("", key, c.Get(key))
+}
+
+// GetFloat returns the single value found for the given key as a float64.
+func (c Collection) GetFloat(key string) (float64, error) {
+	return toFloat("", key, c.Get(key))
+}
+
+// GetBool returns the single value found for the given key as a bool.
+// In addition to the values accepted by strconv.ParseBool, yes/no and on/off are accepted.
+func (c Collection) GetBool(key string) (bool, error) {
+	return toBool("", key, c.Get(key))
+}
+
+// GetDuration returns the single value found for the given key as a time.Duration.
+// Plain numbers are treated as seconds.
+func (c Collection) GetDuration(key string) (time.Duration, error) {
+	return toDuration("", key, c.Get(key))
+}
+
+// GetPort returns the single value found for the given key as a port number between 1 and 65535.
+func (c Collection) GetPort(key string) (int, error) {
+	return toPort("", key, c.Get(key))
+}
+
+// GetPath returns the single value found for the given key as a cleaned absolute path.
+func (c Collection) GetPath(key string) (string, error) {
+	return toPath("", key, c.Get(key))
+}
+
+// GetStringFromPkg returns the single value found for the given pkg and key.
+func (c Collection) GetStringFromPkg(pkg, key string) (string, error) {
+	return single(pkg, key, c.GetFromPkg(pkg, key))
+}
+
+// GetIntFromPkg returns the single value found for the given pkg and key as an int.
+func (c Collection) GetIntFromPkg(pkg, key string) (int, error) {
+	return toInt(pkg, key, c.GetFromPkg(pkg, key))
+}
+
+// GetFloatFromPkg returns the single value found for the given pkg and key as a float64.
+func (c Collection) GetFloatFromPkg(pkg, key string) (float64, error) {
+	return toFloat(pkg, key, c.GetFromPkg(pkg, key))
+}
+
+// GetBoolFromPkg returns the single value found for the given pkg and key as a bool.
+func (c Collection) GetBoolFromPkg(pkg, key string) (bool, error) {
+	return toBool(pkg, key, c.GetFromPkg(pkg, key))
+}
+
+// GetDurationFromPkg returns the single value found for the given pkg and key as a time.Duration.
+func (c Collection) GetDurationFromPkg(pkg, key string) (time.Duration, error) {
+	return toDuration(pkg, key, c.GetFromPkg(pkg, key))
+}
+
+// GetPortFromPkg returns the single value found for the given pkg and key as a port number.
+func (c Collection) GetPortFromPkg(pkg, key string) (int, error) {
+	return toPort(pkg, key, c.GetFromPkg(pkg, key))
+}
+
+// GetPathFromPkg returns the single value found for the given pkg and key as a cleaned absolute path.
+func (c Collection) GetPathFromPkg(pkg, key string) (string, error) {
+	return toPath(pkg, key, c.GetFromPkg(pkg, key))
+}
+
+func single(pkg, key string, values []string) (string, error) {
+	values = filterUnique(values)
+	switch len(values) {
+	case 0:
+		return "", &ValueError{Kind: ValueMissing, Pkg: pkg, Key: key}
+	case 1:
+		return values[0], nil
+	}
+	return "", &ValueError{Kind: ValueAmbiguous, Pkg: pkg, Key: key, Values: values}
+}
+
+func invalid(pkg, key, value string, err error) error {
+	return &ValueError{Kind: ValueInvalid, Pkg: pkg, Key: key, Values: []string{value}, Err: err}
+}
+
+func toInt(pkg, key string, values []string) (int, error) {
+	v, err := single(pkg, key, values)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return 0, invalid(pkg, key, v, err)
+	}
+	return i, nil
+}
+
+func toFloat(pkg, key string, values []string) (float64, error) {
+	v, err := single(pkg, key, values)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return 0, invalid(pkg, key, v, err)
+	}
+	return f, nil
+}
+
+func toBool(pkg, key string, values []string) (bool, error) {
+	v, err := single(pkg, key, values)
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case `yes`, `on`:
+		return true, nil
+	case `no`, `off`:
+		return false, nil
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(v))
+	if err != nil {
+		return false, invalid(pkg, key, v, err)
+	}
+	return b, nil
+}
+
+func toDuration(pkg, key string, values []string) (time.Duration, error) {
+	v, err := single(pkg, key, values)
+	if err != nil {
+		return 0, err
+	}
+	s := strings.TrimSpace(v)
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, invalid(pkg, key, v, err)
+	}
+	return d, nil
+}
+
+func toPort(pkg, key string, values []string) (int, error) {
+	port, err := toInt(pkg, key, values)
+	if err != nil {
+		return 0, err
+	}
+	if port < 1 || port > 65535 {
+		return 0, invalid(pkg, key, strconv.Itoa(port), fmt.Errorf("port out of range"))
+	}
+	return port, nil
+}
+
+func toPath(pkg, key string, values []string) (string, error) {
+	v, err := single(pkg, key, values)
+	if err != nil {
+		return "", err
+	}
+	p := strings.TrimSpace(v)
+	if !path.IsAbs(p) {
+		return "", invalid(pkg, key, v, fmt.Errorf("path is not absolute"))
+	}
+	return path.Clean(p), nil
+}
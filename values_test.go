@@ -0,0 +1,67 @@
+package appconfig
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTypedValues(t *testing.T) {
+	pkg := `packapi-sit20191024.103-0`
+	c := Collection{
+		{Pkg: pkg, Key: `uptime_days`, Value: `17`},
+		{Pkg: pkg, Key: `memorysize_mb`, Value: `3789.76`},
+		{Pkg: pkg, Key: `ports__ENVOY_HTTP_PORT`, Value: `8000`},
+		{Pkg: pkg, Key: `properties__deq-ack-timeout`, Value: `30`},
+		{Pkg: pkg, Key: `environment__e_ir`, Value: `/example/srv-wm-app-packapi/`},
+		{Pkg: pkg, Key: `enabled`, Value: `yes`},
+		{Pkg: pkg, Key: `timezone`, Value: `EDT`},
+		{Pkg: pkg, Key: `dupe`, Value: `1`},
+		{Pkg: `other-sit20191024.103-0`, Key: `dupe`, Value: `2`},
+	}
+	i, err := c.GetInt(`uptime_days`)
+	if err != nil || i != 17 {
+		t.Fatalf("unexpected int: %v, %v", i, err)
+	}
+	f, err := c.GetFloat(`memorysize_mb`)
+	if err != nil || f != 3789.76 {
+		t.Fatalf("unexpected float: %v, %v", f, err)
+	}
+	port, err := c.GetPortFromPkg(pkg, `ports__ENVOY_HTTP_PORT`)
+	if err != nil || port != 8000 {
+		t.Fatalf("unexpected port: %v, %v", port, err)
+	}
+	d, err := c.GetDuration(`properties__deq-ack-timeout`)
+	if err != nil || d != 30*time.Second {
+		t.Fatalf("unexpected duration: %v, %v", d, err)
+	}
+	p, err := c.GetPath(`environment__e_ir`)
+	if err != nil || p != `/example/srv-wm-app-packapi` {
+		t.Fatalf("unexpected path: %v, %v", p, err)
+	}
+	b, err := c.GetBool(`enabled`)
+	if err != nil || !b {
+		t.Fatalf("unexpected bool: %v, %v", b, err)
+	}
+	errKind := func(err error) ValueErrorKind {
+		vErr, ok := err.(*ValueError)
+		if !ok {
+			t.Fatalf("expected *ValueError, got %T", err)
+		}
+		return vErr.Kind
+	}
+	_, err = c.GetInt(`nope`)
+	if errKind(err) != ValueMissing {
+		t.Fatalf("expected missing error, got %v", err)
+	}
+	_, err = c.GetInt(`dupe`)
+	if errKind(err) != ValueAmbiguous {
+		t.Fatalf("expected ambiguous error, got %v", err)
+	}
+	if i, err := c.GetIntFromPkg(pkg, `dupe`); err != nil || i != 1 {
+		t.Fatalf("unexpected pkg scoped int: %v, %v", i, err)
+	}
+	_, err = c.GetInt(`timezone`)
+	if errKind(err) != ValueInvalid {
+		t.Fatalf("expected invalid error, got %v", err)
+	}
+}